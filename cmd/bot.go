@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/bot"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	botCommands []string
+	botOptions  []string
+	botTimeout  time.Duration
+	botDelay    time.Duration
+	botHeadless bool
+	botGames    int
+	botMaxTurns int
+)
+
+func init() {
+	botCmd.Flags().StringArrayVar(&botCommands, "cmd", nil, "bot command, once per player")
+	botCmd.Flags().StringArrayVarP(&botOptions, "option", "o", nil, "game option as key=value, see 'arcade list'")
+	botCmd.Flags().DurationVar(&botTimeout, "timeout", time.Second, "time a bot has to answer, 0 for no limit")
+	botCmd.Flags().DurationVar(&botDelay, "delay", 500*time.Millisecond, "pause between turns of turn-based games when watching")
+	botCmd.Flags().BoolVar(&botHeadless, "headless", false, "play without drawing the game and print a summary")
+	botCmd.Flags().IntVarP(&botGames, "games", "n", 1, "number of games to play headless")
+	botCmd.Flags().IntVar(&botMaxTurns, "max-turns", bot.DefaultMaxTurns, "end games after this many turns or ticks")
+	botCmd.MarkFlagRequired("cmd")
+	rootCmd.AddCommand(botCmd)
+}
+
+var botCmd = &cobra.Command{
+	Use:   "bot <game> --cmd <program>",
+	Short: "Let a program play a game",
+	Long: `Let external programs play a game. Each bot is started once and reads
+one JSON object per line on its stdin:
+
+  {"type":"turn","game":"chess","player":0,"turn":0,"state":{...},"legal":["e2e4",...]}
+  {"type":"end","game":"chess","player":0,"turn":57,"state":{...},"result":{"score":0,"outcome":"win","winner":"White"}}
+
+It answers every turn with a line that echoes the turn, such as
+{"turn":0,"action":"e2e4"}; answers to earlier turns are ignored.
+Real-time games (snake, tetris) send a turn every tick and also accept
+several actions, {"turn":12,"actions":["rotate","left","drop"]}, or
+none, {"turn":12}.
+
+Pass --cmd once per player. A single bot plays every side of chess and
+snake versus, and X against the computer in tic-tac-toe. In turn-based
+games a bot that answers late or with an illegal move forfeits; in
+real-time games the answer is skipped.`,
+	Example: `  arcade bot tictactoe --cmd ./mybot
+  arcade bot chess --cmd ./white --cmd ./black --headless -n 10
+  arcade bot snake -o mode=versus --cmd "python3 snake.py" --cmd ./other`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		gameID := args[0]
+		if _, exists := core.Games[gameID]; !exists {
+			fmt.Fprintf(os.Stderr, "Game %s does not exist\n", gameID)
+			os.Exit(1)
+		}
+
+		options, err := parseOptions(botOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		// Bots can't pick snake's mode from its menu
+		if gameID == "snake" && options["mode"] == "" {
+			options["mode"] = "classic"
+		}
+
+		if err := playBots(gameID, options); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// playBots starts the bots and lets them play, stopping them again when
+// done
+func playBots(gameID string, options core.Options) error {
+	// Bots may log to stderr, which would garble the game on screen
+	var stderr io.Writer
+	if botHeadless {
+		stderr = os.Stderr
+	}
+
+	var bots []*bot.Bot
+	defer func() {
+		for _, b := range bots {
+			b.Close()
+		}
+	}()
+	for _, command := range botCommands {
+		b, err := bot.Start(command, stderr)
+		if err != nil {
+			return err
+		}
+		bots = append(bots, b)
+	}
+
+	if botHeadless {
+		return runBotGames(gameID, options, bots)
+	}
+	return watchBotGame(gameID, options, bots)
+}
+
+// newBotMatch creates a game for the bots to play
+func newBotMatch(gameID string, options core.Options, bots []*bot.Bot) (core.Game, *bot.Match, error) {
+	game, match, err := bot.NewGame(gameID, options, bots)
+	if err != nil {
+		return nil, nil, err
+	}
+	match.Timeout = botTimeout
+	match.MaxTurns = botMaxTurns
+	return game, match, nil
+}
+
+func watchBotGame(gameID string, options core.Options, bots []*bot.Bot) error {
+	game, match, err := newBotMatch(gameID, options, bots)
+	if err != nil {
+		return err
+	}
+
+	p := tea.NewProgram(ui.NewBotViewer(game, match, botDelay), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+	if match.Over() {
+		fmt.Println(match.Describe())
+	}
+	return nil
+}
+
+// botTotals adds up one seat over several games
+type botTotals struct {
+	name                string
+	bot                 string
+	wins, losses, draws int
+	answers             int
+	moves, illegal      int
+	timeouts            int
+	thinking            time.Duration
+	bestScore, scores   int
+}
+
+func runBotGames(gameID string, options core.Options, bots []*bot.Bot) error {
+	seed, seeded := options[core.SeedOption]
+	var totals []botTotals
+	start := time.Now()
+
+	for i := range max(botGames, 1) {
+		// Vary a given seed so the games aren't all the same
+		if seeded {
+			n, err := strconv.ParseUint(seed, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid seed '%s'", seed)
+			}
+			options[core.SeedOption] = strconv.FormatUint(n+uint64(i), 10)
+		}
+
+		_, match, err := newBotMatch(gameID, options, bots)
+		if err != nil {
+			return err
+		}
+		result := match.Run()
+
+		fmt.Printf("Game %d: %s, score %d, %d turns\n", i+1, match.Describe(), result.Score, match.Turns())
+
+		if totals == nil {
+			for player, seat := range match.Players() {
+				totals = append(totals, botTotals{name: match.PlayerName(player), bot: seat.Name})
+			}
+		}
+		for player, seat := range match.Players() {
+			t := &totals[player]
+			switch match.OutcomeFor(player) {
+			case engine.OutcomeWin:
+				t.wins++
+			case engine.OutcomeLoss:
+				t.losses++
+			case engine.OutcomeDraw:
+				t.draws++
+			}
+			t.answers += seat.Answers
+			t.moves += seat.Moves
+			t.illegal += seat.Illegal
+			t.timeouts += seat.Timeouts
+			t.thinking += seat.Thinking
+			t.scores += result.Score
+			t.bestScore = max(t.bestScore, result.Score)
+		}
+	}
+
+	games := max(botGames, 1)
+	fmt.Printf("\n%s, %d games in %s\n", gameID, games, time.Since(start).Round(time.Millisecond))
+	if len(totals) == 1 {
+		fmt.Printf("  %-10s %-20s %7s %9s %7s %8s %9s  %s\n", "PLAYER", "BOT", "BEST", "AVERAGE", "MOVES", "ILLEGAL", "TIMEOUTS", "AVG. ANSWER")
+	} else {
+		fmt.Printf("  %-10s %-20s %7s %9s %7s %7s %8s %9s  %s\n", "PLAYER", "BOT", "WINS", "LOSSES", "DRAWS", "MOVES", "ILLEGAL", "TIMEOUTS", "AVG. ANSWER")
+	}
+	for _, t := range totals {
+		var answer time.Duration
+		if t.answers > 0 {
+			answer = t.thinking / time.Duration(t.answers)
+		}
+		if len(totals) == 1 {
+			fmt.Printf("  %-10s %-20s %7d %9.1f %7d %8d %9d  %s\n",
+				t.name, t.bot, t.bestScore, float64(t.scores)/float64(games), t.moves, t.illegal, t.timeouts, answer.Round(time.Microsecond))
+		} else {
+			fmt.Printf("  %-10s %-20s %7d %9d %7d %7d %8d %9d  %s\n",
+				t.name, t.bot, t.wins, t.losses, t.draws, t.moves, t.illegal, t.timeouts, answer.Round(time.Microsecond))
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jakmaz/arcade/internal/core"
 	"github.com/spf13/cobra"
@@ -28,5 +29,16 @@ func listGames() {
 
 	for _, game := range games {
 		fmt.Printf("  %-12s %s\n", game.ID, game.Description)
+		for _, option := range game.Options {
+			values := strings.Join(option.Values, "|")
+			if values == "" {
+				values = "<value>"
+			}
+			help := option.Help
+			if option.Default != "" {
+				help += fmt.Sprintf(" (default %s)", option.Default)
+			}
+			fmt.Printf("  %-12s   -o %s=%s  %s\n", "", option.Key, values, help)
+		}
 	}
 }
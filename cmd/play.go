@@ -3,26 +3,30 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/achievements"
 	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-// GameWrapper wraps a game model to handle universal exit controls for direct launches
+// GameWrapper wraps a game session to handle universal exit controls for direct launches
 type GameWrapper struct {
-	game tea.Model
+	session *ui.Session
+	saveErr error
 }
 
-// NewGameWrapper creates a new wrapper around a game
-func NewGameWrapper(game tea.Model) *GameWrapper {
+// NewGameWrapper creates a new wrapper around a game session
+func NewGameWrapper(session *ui.Session) *GameWrapper {
 	return &GameWrapper{
-		game: game,
+		session: session,
 	}
 }
 
 func (gw *GameWrapper) Init() tea.Cmd {
-	return gw.game.Init()
+	return gw.session.Init()
 }
 
 func (gw *GameWrapper) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -31,21 +35,28 @@ func (gw *GameWrapper) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle universal exit controls
 		switch msg.String() {
 		case "esc", "ctrl+c", "q":
+			// Keep an unfinished game to resume later
+			gw.saveErr = gw.session.Save()
 			return gw, tea.Quit
 		}
 	}
 
-	// Delegate to the wrapped game
-	var cmd tea.Cmd
-	gw.game, cmd = gw.game.Update(msg)
-	return gw, cmd
+	// Delegate to the session, which handles pausing and game over
+	return gw, gw.session.Update(msg)
 }
 
 func (gw *GameWrapper) View() string {
-	return gw.game.View()
+	return gw.session.View()
 }
 
+var (
+	playOptions []string
+	playResume  bool
+)
+
 func init() {
+	playCmd.Flags().StringArrayVarP(&playOptions, "option", "o", nil, "game option as key=value, see 'arcade list'")
+	playCmd.Flags().BoolVar(&playResume, "resume", false, "continue the saved game")
 	rootCmd.AddCommand(playCmd)
 }
 
@@ -62,23 +73,76 @@ var playCmd = &cobra.Command{
 			fmt.Printf("Game %s does not exist\n", gameID)
 			os.Exit(1)
 		}
-		playGame(gameID)
+
+		if playResume {
+			if len(playOptions) > 0 {
+				fmt.Fprintln(os.Stderr, "Error: a resumed game keeps its saved options")
+				os.Exit(1)
+			}
+			session, err := ui.ResumeSession(gameID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			runSession(session)
+			return
+		}
+
+		options, err := parseOptions(playOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		playGame(gameID, options)
 	},
 }
 
-func playGame(gameID string) {
-	game := core.CreateGame(gameID)
-	if game == nil {
-		fmt.Printf("Game '%s' not found\n", gameID)
-		return
+// parseOptions turns key=value flags into game options
+func parseOptions(flags []string) (core.Options, error) {
+	options := core.Options{}
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, fmt.Errorf("option '%s' is not in key=value form", flag)
+		}
+		options[key] = value
 	}
+	return options, nil
+}
 
+func playGame(gameID string, options core.Options) {
+	session, err := ui.NewSession(gameID, options)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	runSession(session)
+}
+
+func runSession(session *ui.Session) {
 	// Wrap the game to handle exit controls
-	wrappedGame := NewGameWrapper(game)
+	wrappedGame := NewGameWrapper(session)
 
 	p := tea.NewProgram(wrappedGame, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	stopWatching := ui.WatchThemes(p)
+
+	_, err := p.Run()
+	stopWatching()
+	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+
+	if wrappedGame.saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: game not saved: %v\n", wrappedGame.saveErr)
+	}
+	saveAchievements()
+}
+
+// saveAchievements saves achievement progress made too close to exiting
+// for the game to save it
+func saveAchievements() {
+	if err := achievements.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: achievements not saved: %v\n", err)
+	}
 }
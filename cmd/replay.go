@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/replay"
+	"github.com/jakmaz/arcade/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var replaySpeed float64
+
+func init() {
+	replayCmd.Flags().Float64VarP(&replaySpeed, "speed", "s", 1, "playback speed, e.g. 0.5 or 4")
+	rootCmd.AddCommand(replayCmd)
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Watch a recorded game",
+	Long: `Play back a recorded game. Every finished game is recorded in the
+replays directory under the arcade data directory
+(~/.local/share/arcade/replays by default).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := replay.Read(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		player, err := ui.NewReplayPlayer(r, replaySpeed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		p := tea.NewProgram(player, tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -3,9 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/config"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/theme"
 	"github.com/jakmaz/arcade/internal/ui"
+	"github.com/jakmaz/arcade/internal/ui/styles"
 	"github.com/spf13/cobra"
 )
 
@@ -13,16 +18,65 @@ var rootCmd = &cobra.Command{
 	Use:   "arcade",
 	Short: "Classic games in your terminal",
 	Long:  `Arcade is a games collection in your terminal`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		p := tea.NewProgram(ui.NewApp(), tea.WithAltScreen())
+		stopWatching := ui.WatchThemes(p)
 
-		if _, err := p.Run(); err != nil {
+		_, err := p.Run()
+		stopWatching()
+		if err != nil {
 			fmt.Println("Error running program:", err)
 			os.Exit(1)
 		}
+		saveAchievements()
 	},
 }
 
+// loadConfig loads the themes, reads the user's settings and applies the
+// theme. Problems are reported but don't stop arcade from starting.
+func loadConfig() {
+	theme.Initialize()
+	for _, err := range theme.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+
+	if cfg.Theme != "" {
+		if err := theme.SetCurrentTheme(cfg.Theme); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: config.yaml: %v\n", err)
+		} else {
+			styles.RefreshStyles()
+		}
+	}
+
+	for gameID, options := range cfg.Games {
+		info, exists := core.Games[gameID]
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Warning: config.yaml: game '%s' not found\n", gameID)
+			continue
+		}
+		for key, value := range options {
+			if key == config.ColorblindKey {
+				if _, err := strconv.ParseBool(value); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: config.yaml: %s: invalid colorblind setting '%s', expected true or false\n", info.Name, value)
+				}
+				continue
+			}
+			if err := info.CheckOption(key, value); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: config.yaml: %v\n", err)
+			}
+		}
+	}
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
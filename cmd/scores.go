@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/scores"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scoresMode   string
+	scoresPlayer string
+	scoresSince  string
+	scoresUntil  string
+	scoresFormat string
+	scoresLimit  int
+)
+
+func init() {
+	scoresCmd.Flags().StringVar(&scoresMode, "mode", "", "only show games played in this mode")
+	scoresCmd.Flags().StringVar(&scoresPlayer, "player", "", "only show games of this player")
+	scoresCmd.Flags().StringVar(&scoresSince, "since", "", "only show games played on or after this date (YYYY-MM-DD)")
+	scoresCmd.Flags().StringVar(&scoresUntil, "until", "", "only show games played on or before this date (YYYY-MM-DD)")
+	scoresCmd.Flags().StringVarP(&scoresFormat, "format", "f", "table", "output format: table, json or csv")
+	scoresCmd.Flags().IntVarP(&scoresLimit, "limit", "n", 10, "entries per game and mode, 0 for all")
+	rootCmd.AddCommand(scoresCmd)
+}
+
+var scoresCmd = &cobra.Command{
+	Use:   "scores [game]",
+	Short: "Show high scores",
+	Long:  "Print the leaderboards of recorded games, optionally filtered by game, mode, player and date",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, err := scoresFilter(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		records, err := scores.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		records = scores.Leaderboards(records, filter, scoresLimit)
+
+		switch scoresFormat {
+		case "table":
+			printScoresTable(os.Stdout, records)
+		case "json":
+			err = printScoresJSON(os.Stdout, records)
+		case "csv":
+			err = printScoresCSV(os.Stdout, records)
+		default:
+			err = fmt.Errorf("unknown format %q, expected table, json or csv", scoresFormat)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func scoresFilter(args []string) (scores.Filter, error) {
+	filter := scores.Filter{Mode: scoresMode, Player: scoresPlayer}
+
+	if len(args) == 1 {
+		if _, exists := core.Games[args[0]]; !exists {
+			return filter, fmt.Errorf("game %s does not exist", args[0])
+		}
+		filter.Game = args[0]
+	}
+
+	if scoresSince != "" {
+		since, err := time.ParseInLocation(time.DateOnly, scoresSince, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --since date %q, expected YYYY-MM-DD", scoresSince)
+		}
+		filter.Since = since
+	}
+	if scoresUntil != "" {
+		until, err := time.ParseInLocation(time.DateOnly, scoresUntil, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --until date %q, expected YYYY-MM-DD", scoresUntil)
+		}
+		// Include the whole last day
+		filter.Until = until.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+func printScoresTable(w io.Writer, records []scores.Record) {
+	if len(records) == 0 {
+		fmt.Fprintln(w, "No games found")
+		return
+	}
+
+	rank := 0
+	for i, r := range records {
+		if i == 0 || r.Game != records[i-1].Game || r.Mode != records[i-1].Mode {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			title := r.Game
+			if r.Mode != "" {
+				title += " (" + r.Mode + ")"
+			}
+			fmt.Fprintln(w, title)
+			fmt.Fprintf(w, "  %-4s %-16s %7s  %-7s %8s  %s\n", "#", "PLAYER", "SCORE", "RESULT", "TIME", "DATE")
+			rank = 0
+		}
+		rank++
+
+		// Games with a winner aren't scored
+		score := "-"
+		if r.Outcome == "" {
+			score = strconv.Itoa(r.Score)
+		}
+		fmt.Fprintf(w, "  %-4d %-16s %7s  %-7s %8s  %s\n",
+			rank, r.Player, score, r.Outcome, scores.FormatDuration(r.Duration), r.Date.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+// scoreRow is a record as written by the json and csv formats, with
+// plain units that are easy to consume from scripts
+type scoreRow struct {
+	Game            string            `json:"game"`
+	Mode            string            `json:"mode"`
+	Options         map[string]string `json:"options"`
+	Player          string            `json:"player"`
+	Score           int               `json:"score"`
+	Outcome         string            `json:"outcome"`
+	DurationSeconds int               `json:"duration_seconds"`
+	Date            time.Time         `json:"date"`
+}
+
+func newScoreRow(r scores.Record) scoreRow {
+	options := r.Options
+	if options == nil {
+		options = map[string]string{}
+	}
+	return scoreRow{
+		Game:            r.Game,
+		Mode:            r.Mode,
+		Options:         options,
+		Player:          r.Player,
+		Score:           r.Score,
+		Outcome:         r.Outcome,
+		DurationSeconds: int(r.Duration.Seconds()),
+		Date:            r.Date,
+	}
+}
+
+func printScoresJSON(w io.Writer, records []scores.Record) error {
+	rows := make([]scoreRow, 0, len(records))
+	for _, r := range records {
+		rows = append(rows, newScoreRow(r))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+func printScoresCSV(w io.Writer, records []scores.Record) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"game", "mode", "options", "player", "score", "outcome", "duration_seconds", "date"})
+
+	for _, r := range records {
+		row := newScoreRow(r)
+		writer.Write([]string{
+			row.Game,
+			row.Mode,
+			formatOptions(row.Options),
+			row.Player,
+			strconv.Itoa(row.Score),
+			row.Outcome,
+			strconv.Itoa(row.DurationSeconds),
+			row.Date.Format(time.RFC3339),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatOptions writes game options as key=value pairs in a stable order
+func formatOptions(options map[string]string) string {
+	var pairs []string
+	for key, value := range options {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
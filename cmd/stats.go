@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/scores"
+	"github.com/jakmaz/arcade/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [game]",
+	Short: "Show player statistics",
+	Long:  "Show totals for every game computed from the recorded game history",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		games := core.AvailableGames()
+		if len(args) == 1 {
+			game, exists := core.Games[args[0]]
+			if !exists {
+				fmt.Fprintf(os.Stderr, "Game %s does not exist\n", args[0])
+				os.Exit(1)
+			}
+			games = []core.GameInfo{game}
+		}
+
+		records, err := scores.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		for i, game := range games {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(game.Name)
+			for _, line := range ui.FormatStats(scores.Summarize(records, game.ID)) {
+				fmt.Println("  " + line)
+			}
+		}
+	},
+}
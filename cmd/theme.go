@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/config"
 	"github.com/jakmaz/arcade/internal/theme"
+	"github.com/jakmaz/arcade/internal/ui"
 	"github.com/jakmaz/arcade/internal/ui/styles"
 	"github.com/spf13/cobra"
 )
@@ -18,7 +24,14 @@ var themeCmd = &cobra.Command{
 var listThemesCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available themes",
-	Long:  `List all available themes in the arcade`,
+	Long: `List all available themes in the arcade and where they come from.
+Themes are loaded from these places, later ones overriding themes of
+the same name:
+
+  1. the built-in themes
+  2. the themes directory in the config directory
+     (~/.config/arcade/themes by default)
+  3. the files and directories in $ARCADE_THEME_PATH, separated by ':'`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Initialize themes
 		if err := theme.Initialize(); err != nil {
@@ -26,16 +39,20 @@ var listThemesCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		themes := theme.ListThemes()
 		currentTheme := theme.GetCurrentTheme()
 
 		fmt.Println("Available themes:")
-		for _, name := range themes {
-			if currentTheme != nil && name == currentTheme.Name() {
-				fmt.Printf("* %s (current)\n", name)
-			} else {
-				fmt.Printf("  %s\n", name)
+		for _, source := range theme.Sources() {
+			marker, current := " ", ""
+			if currentTheme != nil && source.Name == currentTheme.Name() {
+				marker, current = "*", " (current)"
+			}
+
+			origin := source.Source
+			if len(source.Overrides) > 0 {
+				origin += ", overrides " + strings.Join(source.Overrides, ", ")
 			}
+			fmt.Printf("%s %-16s %s%s\n", marker, source.Name, origin, current)
 		}
 	},
 }
@@ -62,6 +79,11 @@ var setThemeCmd = &cobra.Command{
 		// Refresh styles to use new theme
 		styles.RefreshStyles()
 
+		if err := config.Update(func(c *config.Config) { c.Theme = themeName }); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving theme: %v\n", err)
+			os.Exit(1)
+		}
+
 		fmt.Printf("Theme set to: %s\n", themeName)
 	},
 }
@@ -87,45 +109,250 @@ var previewThemeCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Create styles with this theme
-		themeStyles := theme.NewStylesWithTheme(themeObj)
-
 		fmt.Printf("Theme: %s\n\n", themeObj.Name())
+		fmt.Println(ui.ThemeSamples(themeObj))
+	},
+}
+
+var validateThemeCmd = &cobra.Command{
+	Use:   "validate [file...]",
+	Short: "Check theme files for mistakes",
+	Long: `Check theme files for unknown keys, invalid hex colors, colors missing
+from the palette, a missing name, and themes that extend each other or a
+theme that doesn't exist. Without files, checks the user's themes (see
+'arcade theme list'). Exits with status 1 if there are problems.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		files := args
+		if len(files) == 0 {
+			files = theme.UserThemeFiles()
+			if len(files) == 0 {
+				fmt.Println("No user themes found")
+				return
+			}
+		}
+
+		problems, inherited := theme.ValidateExtends(files)
+		extendsProblems := map[string][]theme.Problem{}
+		for _, problem := range problems {
+			extendsProblems[problem.Path] = append(extendsProblems[problem.Path], problem)
+		}
+
+		failed := 0
+		for _, file := range files {
+			problems, err := theme.ValidateFile(file, inherited[file])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				failed++
+				continue
+			}
+			problems = append(problems, extendsProblems[file]...)
+			if len(problems) == 0 {
+				fmt.Printf("%s: ok\n", file)
+				continue
+			}
+			for _, problem := range problems {
+				fmt.Println(problem)
+			}
+			failed++
+		}
+
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "%d of %d theme files have problems\n", failed, len(files))
+			os.Exit(1)
+		}
+	},
+}
+
+var (
+	checkBackground string
+	checkColorblind bool
+)
+
+var checkThemeCmd = &cobra.Command{
+	Use:   "check [theme-name]",
+	Short: "Check a theme's contrast and colorblind safety",
+	Long: `Check that a theme is easy to read. Reports the WCAG contrast ratio of
+each color with the theme's background, which should be at least 4.5:1
+for text and 3:1 for boards and game pieces, and whether the seven tetris
+pieces and the two players look different with normal vision and with
+protanopia, deuteranopia and tritanopia. Without a name, checks the
+current theme, and with --colorblind the theme as colorblind mode
+draws it. Exits with status 1 if there are problems.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := theme.Initialize(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing themes: %v\n", err)
+			os.Exit(1)
+		}
+
+		themeObj := theme.GetCurrentTheme()
+		if len(args) > 0 {
+			var exists bool
+			if themeObj, exists = theme.GetTheme(args[0]); !exists {
+				fmt.Fprintf(os.Stderr, "Theme '%s' not found\n", args[0])
+				os.Exit(1)
+			}
+		}
+
+		if checkColorblind {
+			themeObj = theme.Colorblind(themeObj)
+		}
+
+		check, err := theme.CheckTheme(themeObj, checkBackground)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Theme: %s on %s\n", themeObj.Name(), check.Background.Hex())
+		if !themeObj.ShouldUseTerminalBackground() && checkBackground == "" {
+			fmt.Println("The theme uses the terminal's background, set yours with --background")
+		}
+
+		fmt.Println("\nContrast:")
+		for _, c := range check.Contrasts {
+			mark := "ok"
+			if !c.OK() {
+				mark = fmt.Sprintf("too low, needs %.1f:1", c.Min)
+			}
+			against := ""
+			if c.Against != "background" {
+				against = " on " + c.Against
+			}
+			fmt.Printf("  %-24s %s %5.2f:1  %s%s\n", c.Role, c.Foreground.Hex(), c.Ratio, mark, against)
+		}
+		if len(check.Skipped) > 0 {
+			fmt.Printf("  Not checked, not hex colors: %s\n", strings.Join(check.Skipped, ", "))
+		}
+
+		fmt.Println("\nColor vision:")
+		for _, vision := range theme.Visions {
+			var confusions []theme.Confusion
+			for _, c := range check.Confusions {
+				if c.Vision == vision {
+					confusions = append(confusions, c)
+				}
+			}
+			if len(confusions) == 0 {
+				fmt.Printf("  %-14s ok\n", vision)
+				continue
+			}
+			fmt.Printf("  %s\n", vision)
+			for _, c := range confusions {
+				fmt.Printf("    %s and %s look alike (difference %.1f, needs %.0f)\n", c.A, c.B, c.Distance, theme.MinColorDistance)
+			}
+		}
+
+		if failures := check.Failures(); failures > 0 {
+			fmt.Fprintf(os.Stderr, "\n%d problems\n", failures)
+			os.Exit(1)
+		}
+	},
+}
+
+var newThemeCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Make a theme in an editor",
+	Long: `Make a theme by changing the colors of an existing one, with a preview
+of every color and of the game boards. The theme is saved to the themes
+directory in the config directory, extending the theme it started from
+with the colors you changed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := theme.Initialize(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing themes: %v\n", err)
+			os.Exit(1)
+		}
+
+		editor := ui.NewThemeEditor()
+		p := tea.NewProgram(editor, tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if name, path := editor.Saved(); path != "" {
+			fmt.Printf("Saved theme %s to %s\n", name, path)
+			fmt.Printf("Use it with 'arcade theme set %s'\n", name)
+		}
+	},
+}
+
+var (
+	importFrom   string
+	importName   string
+	importScheme string
+	importForce  bool
+)
+
+var importThemeCmd = &cobra.Command{
+	Use:   "import <file> --from <format>",
+	Short: "Make a theme from a terminal color scheme",
+	Long: `Make a theme from a terminal color scheme, so arcade matches your
+terminal. The scheme's colors are kept in the theme's palette and given
+roles such as accent, snake food or tetris pieces, and the theme is saved
+to the themes directory in the config directory.
+
+Formats:
+  base16     base16 scheme (.yaml)
+  alacritty  Alacritty config or color scheme (.toml, or the older .yml)
+  kitty      kitty theme or config (.conf)
+  wt         Windows Terminal scheme, or settings.json with --scheme`,
+	Example: `  arcade theme import ~/.config/alacritty/alacritty.toml --from alacritty --name mine
+  arcade theme import dracula.conf --from kitty
+  arcade theme import settings.json --from wt --scheme "One Half Dark"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := args[0]
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		def, err := theme.Import(file, data, importFrom, importScheme)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		// Name the theme after the file if the scheme has no name
+		switch {
+		case importName != "":
+			def.Name = importName
+		case def.Name == "":
+			def.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
 
-		// Show UI colors
-		fmt.Println("UI Colors:")
-		fmt.Printf("  Primary:   %s\n", themeStyles.TitleStyle().Render("Sample Primary Text"))
-		fmt.Printf("  Secondary: %s\n", themeStyles.MenuItemStyle().Render("Sample Secondary Text"))
-		fmt.Printf("  Accent:    %s\n", themeStyles.SelectedItemStyle().Render("Sample Accent Text"))
-		fmt.Printf("  Success:   %s\n", themeStyles.SuccessStyle().Render("Sample Success Text"))
-		fmt.Printf("  Warning:   %s\n", themeStyles.WarningStyle().Render("Sample Warning Text"))
-		fmt.Printf("  Error:     %s\n", themeStyles.ErrorStyle().Render("Sample Error Text"))
-
-		fmt.Println("\nGame Colors:")
-		fmt.Printf("  Player 1:    %s\n", themeStyles.Player1Style().Render("●"))
-		fmt.Printf("  Player 2:    %s\n", themeStyles.Player2Style().Render("●"))
-		fmt.Printf("  Snake Body:  %s\n", themeStyles.SnakeStyle().Render("●"))
-		fmt.Printf("  Snake Head:  %s\n", themeStyles.SnakeHeadStyle().Render("◉"))
-		fmt.Printf("  Food:        %s\n", themeStyles.FoodStyle().Render("◆"))
-
-		fmt.Println("\nChess Pieces:")
-		fmt.Printf("  White: %s\n", themeStyles.WhitePieceStyle().Render("♔ ♕ ♖ ♗ ♘ ♙"))
-		fmt.Printf("  Black: %s\n", themeStyles.BlackPieceStyle().Render("♚ ♛ ♜ ♝ ♞ ♟"))
-
-		fmt.Println("\nTetris Pieces:")
-		fmt.Printf("  I: %s  ", themeStyles.TetrisPieceStyle("I").Render("████"))
-		fmt.Printf("O: %s  ", themeStyles.TetrisPieceStyle("O").Render("██"))
-		fmt.Printf("T: %s  ", themeStyles.TetrisPieceStyle("T").Render("███"))
-		fmt.Printf("S: %s\n", themeStyles.TetrisPieceStyle("S").Render("██"))
-		fmt.Printf("  Z: %s  ", themeStyles.TetrisPieceStyle("Z").Render("██"))
-		fmt.Printf("J: %s  ", themeStyles.TetrisPieceStyle("J").Render("███"))
-		fmt.Printf("L: %s\n", themeStyles.TetrisPieceStyle("L").Render("███"))
+		path, err := theme.SaveTheme(def, fmt.Sprintf("Imported from %s (%s)", filepath.Base(file), importFrom), importForce)
+		if errors.Is(err, theme.ErrThemeExists) {
+			fmt.Fprintf(os.Stderr, "Error: %v, use --force to replace it\n", err)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved theme %s to %s\n", def.Name, path)
+		fmt.Printf("Use it with 'arcade theme set %s'\n", def.Name)
 	},
 }
 
 func init() {
+	checkThemeCmd.Flags().StringVar(&checkBackground, "background", "", "background to check against when the theme uses the terminal's, by default "+theme.DefaultCheckBackground)
+	importThemeCmd.Flags().StringVar(&importFrom, "from", "", "format of the color scheme: "+strings.Join(theme.ImportFormats, ", "))
+	importThemeCmd.Flags().StringVar(&importName, "name", "", "name of the theme, by default the scheme's or the file's")
+	importThemeCmd.Flags().StringVar(&importScheme, "scheme", "", "scheme to import from a Windows Terminal settings file")
+	importThemeCmd.Flags().BoolVarP(&importForce, "force", "f", false, "replace an existing theme file")
+	importThemeCmd.MarkFlagRequired("from")
+
+	checkThemeCmd.Flags().BoolVar(&checkColorblind, "colorblind", false, "check the theme's colors in colorblind mode")
+
 	themeCmd.AddCommand(listThemesCmd)
 	themeCmd.AddCommand(setThemeCmd)
 	themeCmd.AddCommand(previewThemeCmd)
+	themeCmd.AddCommand(validateThemeCmd)
+	themeCmd.AddCommand(checkThemeCmd)
+	themeCmd.AddCommand(importThemeCmd)
+	themeCmd.AddCommand(newThemeCmd)
 	rootCmd.AddCommand(themeCmd)
 }
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/bot"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/tournament"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tournamentGame     string
+	tournamentBots     []string
+	tournamentFormat   string
+	tournamentRounds   int
+	tournamentMoveTime time.Duration
+	tournamentParallel int
+	tournamentOut      string
+	tournamentOptions  []string
+	tournamentMaxTurns int
+)
+
+func init() {
+	tournamentCmd.Flags().StringVarP(&tournamentGame, "game", "g", "chess", "two-player game to play")
+	tournamentCmd.Flags().StringSliceVar(&tournamentBots, "bots", nil, "comma-separated bot commands")
+	tournamentCmd.Flags().StringVar(&tournamentFormat, "format", tournament.RoundRobin, "pairings, round-robin or swiss")
+	tournamentCmd.Flags().IntVarP(&tournamentRounds, "rounds", "r", 1, "times every pair meets in a round-robin, or rounds of a Swiss tournament")
+	tournamentCmd.Flags().DurationVar(&tournamentMoveTime, "move-time", time.Second, "time a bot has for each move; slower bots forfeit")
+	tournamentCmd.Flags().IntVarP(&tournamentParallel, "parallel", "p", runtime.NumCPU(), "games played at the same time")
+	tournamentCmd.Flags().StringVar(&tournamentOut, "out", "", "directory for the standings, PGN and replay files (default tournament-<date>)")
+	tournamentCmd.Flags().StringArrayVarP(&tournamentOptions, "option", "o", nil, "game option as key=value, see 'arcade list'")
+	tournamentCmd.Flags().IntVar(&tournamentMaxTurns, "max-turns", bot.DefaultMaxTurns, "draw games after this many turns or ticks")
+	tournamentCmd.MarkFlagRequired("bots")
+	rootCmd.AddCommand(tournamentCmd)
+}
+
+var tournamentCmd = &cobra.Command{
+	Use:   "tournament --bots <a,b,...>",
+	Short: "Play bots against each other",
+	Long: `Play a tournament between bots without drawing the games. Bots speak
+the protocol described in 'arcade bot --help', and every game starts
+fresh bot processes.
+
+Pairings are round-robin, where everyone meets everyone --rounds times
+with colors swapped each time, or Swiss, where --rounds rounds pair
+bots with similar scores. Entrants start with an Elo rating of 1500.
+
+The standings table, a replay of every game and, for chess, every game
+as PGN are written to the --out directory. Watch a game with
+'arcade replay <file>'.`,
+	Example: `  arcade tournament --bots ./a,./b,./c --rounds 2
+  arcade tournament --game tictactoe --bots ./a,./b,./c,./d,./e --format swiss --rounds 3`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, exists := core.Games[tournamentGame]; !exists {
+			fmt.Fprintf(os.Stderr, "Game %s does not exist\n", tournamentGame)
+			os.Exit(1)
+		}
+		options, err := parseOptions(tournamentOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		// Snake is only a two-player game in versus mode
+		if tournamentGame == "snake" && options["mode"] == "" {
+			options["mode"] = "versus"
+		}
+
+		dir := tournamentOut
+		if dir == "" {
+			dir = "tournament-" + time.Now().Format("20060102-150405")
+		}
+
+		t, err := tournament.New(tournament.Config{
+			Game:     tournamentGame,
+			Options:  options,
+			Format:   tournamentFormat,
+			Rounds:   tournamentRounds,
+			MoveTime: tournamentMoveTime,
+			MaxTurns: tournamentMaxTurns,
+			Parallel: tournamentParallel,
+			Dir:      dir,
+		}, tournamentBots)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		err = t.Run(func(game *tournament.Game) {
+			white := t.Entrants[game.White].Name
+			if game.Bye() {
+				fmt.Printf("Round %d.%d  %s has a bye\n", game.Round, game.Board, white)
+				return
+			}
+			line := fmt.Sprintf("Round %d.%d  %s - %s  %s", game.Round, game.Board, white, t.Entrants[game.Black].Name, game.Score())
+			if game.Reason != "" {
+				line += "  (" + game.Reason + ")"
+			}
+			fmt.Println(line)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println()
+		t.WriteStandings(os.Stdout)
+
+		path, err := t.SaveStandings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\nStandings and games written to %s\n", strings.TrimSuffix(path, "standings.txt"))
+	},
+}
@@ -0,0 +1,221 @@
+// Package achievements unlocks goals reached in the games. It listens to
+// the events games publish on the core event bus and keeps the unlocked
+// achievements in the data directory.
+package achievements
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/paths"
+	"github.com/jakmaz/arcade/internal/storage"
+)
+
+// fileVersion is bumped whenever the file layout changes incompatibly
+const fileVersion = 1
+
+// Progress is what has been achieved so far
+type Progress struct {
+	Version  int                  `json:"version"`
+	Unlocked map[string]time.Time `json:"unlocked"`
+	Counters map[string]int       `json:"counters"`
+}
+
+var (
+	startOnce sync.Once
+
+	mu       sync.Mutex
+	progress Progress     // as loaded at Start, with the events since
+	unsaved  []core.Event // events that changed progress, not saved yet
+	pending  []Achievement
+
+	// saveMu keeps saves in order, one at a time
+	saveMu sync.Mutex
+)
+
+// All returns every achievement, unlocked or not
+func All() []Achievement {
+	all := make([]Achievement, len(rules))
+	for i, r := range rules {
+		all[i] = r.Achievement
+	}
+	return all
+}
+
+// Start loads the saved progress and subscribes to game events. It is
+// safe to call more than once.
+func Start() {
+	startOnce.Do(func() {
+		loaded, err := Load()
+		if err != nil {
+			// Save reports the error once there is progress to save
+			loaded = newProgress()
+		}
+		mu.Lock()
+		progress = loaded
+		mu.Unlock()
+		core.Subscribe(handle)
+	})
+}
+
+// TakeUnlocked returns the achievements unlocked since the last call,
+// for the host to announce, and whether there is progress to Save
+func TakeUnlocked() ([]Achievement, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unlocked := pending
+	pending = nil
+	return unlocked, len(unsaved) > 0
+}
+
+// handle applies an event to the progress in memory. Events that change
+// it are queued for Save, which the host runs off the UI goroutine.
+func handle(event core.Event) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	unlocked, changed := apply(event, progress)
+	if changed {
+		pending = append(pending, unlocked...)
+		unsaved = append(unsaved, event)
+	}
+}
+
+// apply updates progress for an event, returning the achievements it
+// unlocked and whether anything changed
+func apply(event core.Event, progress Progress) ([]Achievement, bool) {
+	changed := tally(event, progress.Counters)
+
+	var unlocked []Achievement
+	for _, r := range rules {
+		if _, done := progress.Unlocked[r.ID]; done || !r.match(event, progress.Counters) {
+			continue
+		}
+		progress.Unlocked[r.ID] = time.Now()
+		unlocked = append(unlocked, r.Achievement)
+		changed = true
+	}
+	return unlocked, changed
+}
+
+// Save writes the progress made since the last save to the progress
+// file. The file is locked and read again first, and the new events are
+// applied on top of it, so other arcade processes don't lose each
+// other's progress. Progress that couldn't be saved is kept for the next
+// call.
+func Save() error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
+	mu.Lock()
+	events := unsaved
+	unsaved = nil
+	mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := save(events); err != nil {
+		mu.Lock()
+		unsaved = append(events, unsaved...)
+		mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func save(events []core.Event) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := storage.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved, err := load(path)
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, event := range events {
+		if _, c := apply(event, saved); c {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode achievements: %w", err)
+	}
+	return storage.WriteFile(path, data)
+}
+
+// Path returns the location of the progress file
+func Path() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "achievements.json"), nil
+}
+
+// Load reads the saved progress
+func Load() (Progress, error) {
+	path, err := Path()
+	if err != nil {
+		return Progress{}, err
+	}
+	return load(path)
+}
+
+// newProgress is the progress before anything is achieved
+func newProgress() Progress {
+	return Progress{
+		Version:  fileVersion,
+		Unlocked: map[string]time.Time{},
+		Counters: map[string]int{},
+	}
+}
+
+func load(path string) (Progress, error) {
+	progress := newProgress()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return progress, nil
+	}
+	if err != nil {
+		return progress, fmt.Errorf("failed to read achievements: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return progress, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if progress.Version > fileVersion {
+		return progress, fmt.Errorf("%s was written by a newer version of arcade", path)
+	}
+
+	// Files may omit empty maps
+	if progress.Unlocked == nil {
+		progress.Unlocked = map[string]time.Time{}
+	}
+	if progress.Counters == nil {
+		progress.Counters = map[string]int{}
+	}
+	progress.Version = fileVersion
+	return progress, nil
+}
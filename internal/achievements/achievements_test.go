@@ -0,0 +1,86 @@
+package achievements
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jakmaz/arcade/internal/core"
+)
+
+// useProgress starts from progress as Start would have loaded it, with
+// the progress file in a temporary data directory
+func useProgress(t *testing.T, loaded Progress) string {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	progress, unsaved, pending = loaded, nil, nil
+	mu.Unlock()
+	return path
+}
+
+func TestHandleKeepsProgressInMemory(t *testing.T) {
+	path := useProgress(t, newProgress())
+
+	handle(core.Event{Game: "tictactoe", Name: "lost_game"})
+	if unlocked, unsaved := TakeUnlocked(); len(unlocked) != 0 || unsaved {
+		t.Errorf("nothing changed, got %v unlocked and unsaved %v", unlocked, unsaved)
+	}
+
+	handle(core.Event{Game: "tetris", Name: "lines_cleared", Value: 4})
+	unlocked, unsaved := TakeUnlocked()
+	if len(unlocked) != 2 || !unsaved {
+		t.Fatalf("got %v unlocked and unsaved %v, want Line Up and Tetris! to save", unlocked, unsaved)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("progress file written before Save: %v", err)
+	}
+
+	if err := Save(); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Unlocked) != 2 || saved.Counters["tetris_lines"] != 4 {
+		t.Errorf("saved %v", saved)
+	}
+	if _, unsaved := TakeUnlocked(); unsaved {
+		t.Error("progress still unsaved after Save")
+	}
+}
+
+// Progress another process saved meanwhile is kept
+func TestSaveMergesProgress(t *testing.T) {
+	path := useProgress(t, newProgress())
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	other := `{"version": 1, "unlocked": {"snake-20": "2026-01-02T00:00:00Z"}, "counters": {"tetris_lines": 98}}`
+	if err := os.WriteFile(path, []byte(other), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	handle(core.Event{Game: "tetris", Name: "lines_cleared", Value: 2})
+	if err := Save(); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Counters["tetris_lines"] != 100 {
+		t.Errorf("tetris_lines = %d, want 100", saved.Counters["tetris_lines"])
+	}
+	for _, id := range []string{"snake-20", "tetris-line", "tetris-centurion"} {
+		if _, ok := saved.Unlocked[id]; !ok {
+			t.Errorf("%s not unlocked in %v", id, saved.Unlocked)
+		}
+	}
+}
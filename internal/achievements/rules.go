@@ -0,0 +1,101 @@
+package achievements
+
+import "github.com/jakmaz/arcade/internal/core"
+
+// Achievement is a goal that unlocks once reached
+type Achievement struct {
+	ID          string
+	Name        string
+	Description string
+}
+
+// rule unlocks an achievement when it matches an event. counters holds
+// the running totals kept by tally, already updated for the event.
+type rule struct {
+	Achievement
+	match func(event core.Event, counters map[string]int) bool
+}
+
+// rules lists every achievement in the order they are shown
+var rules = []rule{
+	{
+		Achievement{"tetris-line", "Line Up", "Clear a line in Tetris"},
+		func(e core.Event, _ map[string]int) bool {
+			return e.Game == "tetris" && e.Name == "lines_cleared"
+		},
+	},
+	{
+		Achievement{"tetris-tetris", "Tetris!", "Clear four lines at once"},
+		func(e core.Event, _ map[string]int) bool {
+			return e.Game == "tetris" && e.Name == "lines_cleared" && e.Value == 4
+		},
+	},
+	{
+		Achievement{"tetris-centurion", "Centurion", "Clear 100 lines in total"},
+		func(_ core.Event, c map[string]int) bool {
+			return c["tetris_lines"] >= 100
+		},
+	},
+	{
+		Achievement{"snake-20", "Growing Up", "Reach length 20 in Snake"},
+		func(e core.Event, _ map[string]int) bool {
+			return e.Game == "snake" && e.Name == "length" && e.Value >= 20
+		},
+	},
+	{
+		Achievement{"snake-50", "Anaconda", "Reach length 50 in Snake"},
+		func(e core.Event, _ map[string]int) bool {
+			return e.Game == "snake" && e.Name == "length" && e.Value >= 50
+		},
+	},
+	{
+		Achievement{"snake-level", "Maze Runner", "Complete a Snake level"},
+		func(e core.Event, _ map[string]int) bool {
+			return e.Game == "snake" && e.Name == "level_complete"
+		},
+	},
+	{
+		Achievement{"chess-checkmate", "Checkmate", "Deliver checkmate"},
+		func(e core.Event, _ map[string]int) bool {
+			return e.Game == "chess" && e.Name == "checkmate"
+		},
+	},
+	{
+		Achievement{"chess-quick", "Blitzkrieg", "Deliver checkmate within 10 moves"},
+		func(e core.Event, _ map[string]int) bool {
+			// Value counts the moves of both sides
+			return e.Game == "chess" && e.Name == "checkmate" && e.Value <= 20
+		},
+	},
+	{
+		Achievement{"tictactoe-streak", "Unbeatable", "Play 3 Tic-Tac-Toe games in a row against the computer without losing"},
+		func(_ core.Event, c map[string]int) bool {
+			return c["tictactoe_streak"] >= 3
+		},
+	},
+	{
+		Achievement{"tictactoe-perfect", "Perfectionist", "Play 10 Tic-Tac-Toe games in a row against the computer without losing"},
+		func(_ core.Event, c map[string]int) bool {
+			return c["tictactoe_streak"] >= 10
+		},
+	},
+}
+
+// tally updates the running totals for an event and reports whether any
+// changed
+func tally(event core.Event, counters map[string]int) bool {
+	switch event.Game + "/" + event.Name {
+	case "tetris/lines_cleared":
+		counters["tetris_lines"] += event.Value
+	case "tictactoe/perfect_game":
+		counters["tictactoe_streak"]++
+	case "tictactoe/lost_game":
+		if counters["tictactoe_streak"] == 0 {
+			return false
+		}
+		counters["tictactoe_streak"] = 0
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+// Package bot plays games against external programs. A bot is any
+// executable that reads game states as JSON lines on its stdin and
+// answers each one with an action on its stdout.
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+var (
+	// ErrTimeout is returned when a bot doesn't answer in time
+	ErrTimeout = errors.New("no answer in time")
+
+	// ErrExited is returned once a bot has stopped
+	ErrExited = errors.New("bot exited")
+)
+
+// Message is a line sent to a bot
+type Message struct {
+	Type   string          `json:"type"` // "turn" or "end"
+	Game   string          `json:"game"`
+	Player int             `json:"player"` // the player the bot acts for, from 0
+	Turn   int             `json:"turn"`
+	State  json.RawMessage `json:"state"`
+	Legal  []engine.Action `json:"legal,omitempty"`
+	Result *Result         `json:"result,omitempty"` // set in "end" messages
+}
+
+// Result is the end of a game as seen by one player
+type Result struct {
+	Score   int    `json:"score"`
+	Outcome string `json:"outcome"` // "win", "loss" or "draw"; empty for solo games
+	Winner  string `json:"winner,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// reply is a line read from a bot. Real-time games accept any number of
+// actions per tick, including none; turn-based games exactly one. Turn
+// echoes the turn answered, so late answers aren't taken for the next.
+type reply struct {
+	Turn    *int            `json:"turn"`
+	Action  engine.Action   `json:"action,omitempty"`
+	Actions []engine.Action `json:"actions,omitempty"`
+}
+
+// Bot is a running bot process
+type Bot struct {
+	Name string
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan string
+	mu    sync.Mutex // one question at a time
+}
+
+// Start runs a bot. command is the program followed by its arguments,
+// separated by spaces. The bot's stderr is copied to stderr, which may be
+// nil to discard it.
+func Start(command string, stderr io.Writer) (*Bot, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty bot command")
+	}
+
+	cmd := exec.Command(fields[0], fields[1:]...)
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start bot: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start bot: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start bot: %w", err)
+	}
+
+	b := &Bot{Name: command, cmd: cmd, stdin: stdin, lines: make(chan string, 16)}
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				b.lines <- line
+			}
+		}
+		close(b.lines)
+	}()
+	return b, nil
+}
+
+// Ask sends a turn to the bot and waits up to timeout for its actions.
+// Answers to earlier turns, which came in too late, are dropped.
+func (b *Bot) Ask(msg Message, timeout time.Duration) ([]engine.Action, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.send(msg); err != nil {
+		return nil, err
+	}
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		select {
+		case line, ok := <-b.lines:
+			if !ok {
+				return nil, ErrExited
+			}
+			var r reply
+			if err := json.Unmarshal([]byte(line), &r); err != nil {
+				return nil, fmt.Errorf("invalid answer %q", line)
+			}
+			if r.Turn == nil {
+				return nil, fmt.Errorf("answer %q doesn't say which turn it is for", line)
+			}
+			if *r.Turn != msg.Turn {
+				continue
+			}
+			if r.Action != "" {
+				r.Actions = append([]engine.Action{r.Action}, r.Actions...)
+			}
+			return r.Actions, nil
+		case <-deadline:
+			return nil, ErrTimeout
+		}
+	}
+}
+
+// Tell sends a message the bot doesn't answer, such as the end of a game
+func (b *Bot) Tell(msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.send(msg)
+}
+
+func (b *Bot) send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := b.stdin.Write(append(data, '\n')); err != nil {
+		return ErrExited
+	}
+	return nil
+}
+
+// Close closes the bot's stdin and gives it a moment to exit before
+// killing it
+func (b *Bot) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stdin.Close()
+
+	// Wait closes stdout, so the reader has to reach its end first. Read
+	// whatever the bot still says until it exits, which also keeps the
+	// reader from blocking on a full channel.
+	kill := time.After(time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-b.lines:
+		case <-kill:
+			b.cmd.Process.Kill()
+			kill = nil
+		}
+	}
+	b.cmd.Wait()
+}
@@ -0,0 +1,428 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/replay"
+)
+
+// DefaultMaxTurns ends games that would otherwise never finish, e.g.
+// when a bot keeps passing
+const DefaultMaxTurns = 100000
+
+// Match plays an engine with bots. Turn-based games ask the player to
+// move, real-time games ask every player each tick and then advance.
+type Match struct {
+	Game     string
+	Timeout  time.Duration // per answer, 0 to wait forever
+	MaxTurns int
+	Replay   *replay.Replay // records the actions and ticks played, if set
+
+	engine  engine.Engine
+	players []Player
+	turn    int
+	over    bool    // set when the game ended early
+	result  *Result // the early ending, from player 0's side
+}
+
+// Player is a seat in a match and how it played
+type Player struct {
+	Name     string // the bot's command, or "computer"
+	Bot      *Bot   // nil for the game's built-in computer player
+	Answers  int
+	Moves    int // actions played
+	Illegal  int
+	Timeouts int
+	Thinking time.Duration // total time taken to answer
+}
+
+// Turn is what the players answered in one turn
+type Turn struct {
+	answers []answer
+}
+
+type answer struct {
+	player  int
+	actions []engine.Action
+	err     error
+	elapsed time.Duration
+}
+
+// NewMatch seats the bots in order. With fewer bots than players, the
+// game's computer takes the remaining seats when it has one, otherwise
+// the bots play more than one side.
+func NewMatch(game string, e engine.Engine, bots []*Bot) (*Match, error) {
+	count := playerCount(e)
+	if len(bots) == 0 {
+		return nil, fmt.Errorf("no bots to play %s", game)
+	}
+	if len(bots) > count {
+		return nil, fmt.Errorf("%s has %d players but %d bots were given", game, count, len(bots))
+	}
+
+	m := &Match{Game: game, MaxTurns: DefaultMaxTurns, engine: e}
+	_, computer := e.(engine.Solver)
+	for i := range count {
+		switch {
+		case i < len(bots):
+			m.players = append(m.players, Player{Name: bots[i].Name, Bot: bots[i]})
+		case computer:
+			m.players = append(m.players, Player{Name: "computer"})
+		default:
+			bot := bots[i%len(bots)]
+			m.players = append(m.players, Player{Name: bot.Name, Bot: bot})
+		}
+	}
+	return m, nil
+}
+
+// NewGame creates a game and seats the bots at its engine. The game
+// draws the match but doesn't play it.
+func NewGame(gameID string, options core.Options, bots []*Bot) (core.Game, *Match, error) {
+	game, err := core.CreateGame(gameID, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	driven, ok := game.(core.Driven)
+	if !ok || driven.Engine() == nil {
+		return nil, nil, fmt.Errorf("%s can't be played by bots with these options", gameID)
+	}
+
+	match, err := NewMatch(gameID, driven.Engine(), bots)
+	if err != nil {
+		return nil, nil, err
+	}
+	return game, match, nil
+}
+
+func playerCount(e engine.Engine) int {
+	switch e := e.(type) {
+	case engine.TurnBased:
+		return e.PlayerCount()
+	case engine.Multiplayer:
+		return e.PlayerCount()
+	}
+	return 1
+}
+
+// Engine returns the engine being played
+func (m *Match) Engine() engine.Engine {
+	return m.engine
+}
+
+// Players returns the seats in player order
+func (m *Match) Players() []Player {
+	return m.players
+}
+
+// Turns is the number of turns or ticks played so far
+func (m *Match) Turns() int {
+	return m.turn
+}
+
+// PlayerName returns the name of a player in the game's terms
+func (m *Match) PlayerName(player int) string {
+	switch e := m.engine.(type) {
+	case engine.TurnBased:
+		return e.PlayerName(player)
+	case engine.Multiplayer:
+		return e.PlayerName(player)
+	}
+	return fmt.Sprintf("Player %d", player+1)
+}
+
+// Realtime reports whether the game advances on its own between turns
+func (m *Match) Realtime() bool {
+	if _, ok := m.engine.(engine.TurnBased); ok {
+		return false
+	}
+	_, ok := m.engine.(engine.Realtime)
+	return ok
+}
+
+// Over reports whether the game has ended
+func (m *Match) Over() bool {
+	return m.over || m.engine.Result().Over
+}
+
+// Result returns the engine's result, or how the game ended early
+func (m *Match) Result() engine.Result {
+	result := m.engine.Result()
+	if m.result != nil {
+		result.Over = true
+		result.Winner = m.result.Winner
+		result.Outcome = parseOutcome(m.result.Outcome)
+	}
+	return result
+}
+
+// Reason explains why a game ended early, empty when it finished normally
+func (m *Match) Reason() string {
+	if m.result == nil {
+		return ""
+	}
+	return m.result.Reason
+}
+
+func parseOutcome(s string) engine.Outcome {
+	for _, outcome := range []engine.Outcome{engine.OutcomeWin, engine.OutcomeLoss, engine.OutcomeDraw} {
+		if outcome.String() == s {
+			return outcome
+		}
+	}
+	return engine.OutcomeNone
+}
+
+// toMove lists the players asked for an action this turn
+func (m *Match) toMove() []int {
+	if e, ok := m.engine.(engine.TurnBased); ok {
+		return []int{e.ToMove()}
+	}
+	players := make([]int, len(m.players))
+	for i := range players {
+		players[i] = i
+	}
+	return players
+}
+
+func (m *Match) legal(player int) []engine.Action {
+	if e, ok := m.engine.(engine.Multiplayer); ok {
+		return e.LegalFor(player)
+	}
+	return m.engine.Legal()
+}
+
+// Ask collects the answers for the next turn without changing the game,
+// so a game can be drawn while the bots think. Players of real-time games
+// are asked at the same time.
+func (m *Match) Ask() Turn {
+	state, err := json.Marshal(m.engine)
+	if err != nil {
+		state = []byte("null")
+	}
+
+	players := m.toMove()
+	turn := Turn{answers: make([]answer, len(players))}
+	var wg sync.WaitGroup
+	for i, player := range players {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			turn.answers[i] = m.ask(player, state)
+		}()
+	}
+	wg.Wait()
+	return turn
+}
+
+func (m *Match) ask(player int, state json.RawMessage) answer {
+	legal := m.legal(player)
+	if len(legal) == 0 {
+		return answer{player: player}
+	}
+
+	seat := m.players[player]
+	if seat.Bot == nil {
+		return answer{player: player, actions: []engine.Action{m.engine.(engine.Solver).BestMove()}}
+	}
+
+	start := time.Now()
+	actions, err := seat.Bot.Ask(Message{
+		Type:   "turn",
+		Game:   m.Game,
+		Player: player,
+		Turn:   m.turn,
+		State:  state,
+		Legal:  legal,
+	}, m.Timeout)
+	return answer{player: player, actions: actions, err: err, elapsed: time.Since(start)}
+}
+
+// Play applies the answers of a turn and advances real-time games by a
+// tick. Players may only play their own legal actions. In turn-based
+// games a player who doesn't answer in time, answers with an illegal move
+// or exits forfeits the game; in real-time games such answers are counted
+// and skipped, and only exiting forfeits.
+func (m *Match) Play(turn Turn) {
+	if m.Over() {
+		return
+	}
+	realtime := m.Realtime()
+
+	for _, a := range turn.answers {
+		seat := &m.players[a.player]
+		if seat.Bot != nil && a.elapsed > 0 {
+			seat.Answers++
+			seat.Thinking += a.elapsed
+		}
+
+		switch {
+		case errors.Is(a.err, ErrTimeout):
+			seat.Timeouts++
+			if !realtime {
+				m.forfeit(a.player, "did not answer in time")
+				return
+			}
+		case errors.Is(a.err, ErrExited):
+			m.forfeit(a.player, "bot exited")
+			return
+		case a.err != nil:
+			seat.Illegal++
+			if !realtime {
+				m.forfeit(a.player, a.err.Error())
+				return
+			}
+		case !realtime && len(a.actions) != 1 && len(m.legal(a.player)) > 0:
+			seat.Illegal++
+			m.forfeit(a.player, fmt.Sprintf("answered with %d moves instead of one", len(a.actions)))
+			return
+		default:
+			for _, action := range a.actions {
+				if err := m.step(a.player, action); err != nil {
+					seat.Illegal++
+					if !realtime {
+						m.forfeit(a.player, fmt.Sprintf("illegal move %q", action))
+						return
+					}
+				} else {
+					seat.Moves++
+					if m.Replay != nil {
+						m.Replay.Action(action)
+					}
+				}
+			}
+		}
+	}
+
+	if rt, ok := m.engine.(engine.Realtime); ok && realtime && !m.Over() {
+		rt.Tick()
+		if m.Replay != nil {
+			m.Replay.Tick()
+		}
+	}
+
+	m.turn++
+	if m.MaxTurns > 0 && m.turn >= m.MaxTurns && !m.Over() {
+		m.end(Result{Outcome: m.drawOutcome(), Reason: "turn limit reached"})
+	}
+}
+
+// step plays an action for player, who may only play the actions listed
+// as theirs, e.g. only steer their own snake
+func (m *Match) step(player int, action engine.Action) error {
+	if !slices.Contains(m.legal(player), action) {
+		return fmt.Errorf("%w: %s", engine.ErrIllegal, action)
+	}
+	return m.engine.Step(action)
+}
+
+// Step plays one turn
+func (m *Match) Step() {
+	m.Play(m.Ask())
+}
+
+// Run plays the game to the end and tells the bots how it went
+func (m *Match) Run() engine.Result {
+	for !m.Over() {
+		m.Step()
+	}
+	m.Finish()
+	return m.Result()
+}
+
+// forfeit ends the game as a loss for player
+func (m *Match) forfeit(player int, reason string) {
+	reason = fmt.Sprintf("%s forfeits: %s", m.PlayerName(player), reason)
+	if len(m.players) != 2 {
+		m.end(Result{Reason: reason})
+		return
+	}
+
+	result := Result{Winner: m.PlayerName(1 - player), Reason: reason}
+	if player == 0 {
+		result.Outcome = engine.OutcomeLoss.String()
+	} else {
+		result.Outcome = engine.OutcomeWin.String()
+	}
+	m.end(result)
+}
+
+func (m *Match) drawOutcome() string {
+	if len(m.players) > 1 {
+		return engine.OutcomeDraw.String()
+	}
+	return ""
+}
+
+func (m *Match) end(result Result) {
+	m.over = true
+	m.result = &result
+}
+
+// Finish tells every bot how the game ended, from its own side
+func (m *Match) Finish() {
+	state, err := json.Marshal(m.engine)
+	if err != nil {
+		state = []byte("null")
+	}
+	result := m.Result()
+
+	var told []*Bot
+	for player, seat := range m.players {
+		if seat.Bot == nil || slices.Contains(told, seat.Bot) {
+			continue
+		}
+		told = append(told, seat.Bot)
+
+		seat.Bot.Tell(Message{
+			Type:   "end",
+			Game:   m.Game,
+			Player: player,
+			Turn:   m.turn,
+			State:  state,
+			Result: &Result{Score: result.Score, Outcome: m.OutcomeFor(player).String(), Winner: result.Winner, Reason: m.Reason()},
+		})
+	}
+}
+
+// OutcomeFor returns how the game ended for player, as Result's Outcome
+// is about player 0
+func (m *Match) OutcomeFor(player int) engine.Outcome {
+	outcome := m.Result().Outcome
+	if player != 1 {
+		return outcome
+	}
+	switch outcome {
+	case engine.OutcomeWin:
+		return engine.OutcomeLoss
+	case engine.OutcomeLoss:
+		return engine.OutcomeWin
+	}
+	return outcome
+}
+
+// Describe sums up how the game ended, e.g. "White wins" or "Draw (turn
+// limit reached)"
+func (m *Match) Describe() string {
+	result := m.Result()
+	var s string
+	switch {
+	case result.Winner != "":
+		s = result.Winner + " wins"
+	case result.Outcome == engine.OutcomeDraw:
+		s = "Draw"
+	default:
+		s = "Game over"
+	}
+	if reason := m.Reason(); reason != "" {
+		s += " (" + reason + ")"
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/games/snake"
+	"github.com/jakmaz/arcade/internal/games/tictactoe"
+)
+
+func newTestMatch(t *testing.T, game string, e engine.Engine, bots int) *Match {
+	t.Helper()
+	var seats []*Bot
+	for i := range bots {
+		seats = append(seats, &Bot{Name: string(rune('a' + i))})
+	}
+	m, err := NewMatch(game, e, seats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// A snake may only be steered by its own seat
+func TestPlayRejectsOtherPlayersActions(t *testing.T) {
+	v := snake.NewVersus(3)
+	m := newTestMatch(t, "snake", v, 2)
+	m.Play(Turn{answers: []answer{
+		{player: 0, actions: []engine.Action{snake.TurnPlayer(1, snake.Up), snake.TurnPlayer(0, snake.Down)}},
+		{player: 1},
+	}})
+
+	if m.Over() {
+		t.Fatalf("match over: %s", m.Describe())
+	}
+	if seat := m.Players()[0]; seat.Illegal != 1 || seat.Moves != 1 {
+		t.Errorf("seat 0 played %d moves with %d illegal, want 1 and 1", seat.Moves, seat.Illegal)
+	}
+	players := v.Players()
+	if got, want := players[0].Body[0], (snake.Position{X: 5, Y: 11}); got != want {
+		t.Errorf("player 1 head at %v, want %v after turning down", got, want)
+	}
+	if got, want := players[1].Body[0], (snake.Position{X: snake.Width - 7, Y: snake.Height / 2}); got != want {
+		t.Errorf("player 2 head at %v, want %v still heading left", got, want)
+	}
+}
+
+func TestPlayForfeitsIllegalMoves(t *testing.T) {
+	m := newTestMatch(t, "tictactoe", tictactoe.NewGame(), 2)
+	m.Play(Turn{answers: []answer{{player: 0, actions: []engine.Action{"d4"}}}})
+
+	if !m.Over() {
+		t.Fatal("match not over after an illegal move")
+	}
+	result := m.Result()
+	if result.Outcome != engine.OutcomeLoss || result.Winner != "O" {
+		t.Errorf("result %+v, want a loss to O", result)
+	}
+	if got, want := m.Reason(), `X forfeits: illegal move "d4"`; got != want {
+		t.Errorf("Reason() = %q, want %q", got, want)
+	}
+}
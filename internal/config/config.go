@@ -0,0 +1,247 @@
+// Package config keeps the user's settings in config.yaml in the arcade
+// config directory
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/jakmaz/arcade/internal/paths"
+	"github.com/jakmaz/arcade/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user's settings
+type Config struct {
+	Theme  string `yaml:"theme,omitempty"`
+	Player string `yaml:"player,omitempty"` // name finished games are recorded under
+
+	// Colorblind draws games with colors and patterns that don't rely on
+	// telling hues apart. Unset is off.
+	Colorblind *bool `yaml:"colorblind,omitempty"`
+
+	// Games holds default options per game, as accepted by
+	// 'arcade play -o key=value', and "colorblind" to override the
+	// Colorblind setting for the game
+	Games map[string]map[string]string `yaml:"games,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	current *Config
+)
+
+// Path returns the location of the config file
+func Path() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// Load reads the config file, which may not exist yet, and makes it the
+// config returned by Get
+func Load() (Config, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	c, _, err := read()
+	current = &c
+	return c, err
+}
+
+// Get returns the settings, reading the config file the first time. A
+// file that can't be read counts as empty; Load reports why.
+func Get() Config {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if current == nil {
+		c, _, _ := read()
+		current = &c
+	}
+	return *current
+}
+
+// GameOptions returns the default options of a game
+func GameOptions(gameID string) map[string]string {
+	return Get().Games[gameID]
+}
+
+// SetGameOption sets a default option of a game, or clears it when value
+// is empty
+func (c *Config) SetGameOption(gameID, key, value string) {
+	if value == "" {
+		delete(c.Games[gameID], key)
+		if len(c.Games[gameID]) == 0 {
+			delete(c.Games, gameID)
+		}
+		return
+	}
+	if c.Games == nil {
+		c.Games = map[string]map[string]string{}
+	}
+	if c.Games[gameID] == nil {
+		c.Games[gameID] = map[string]string{}
+	}
+	c.Games[gameID][key] = value
+}
+
+// ColorblindKey sets colorblind mode in a game's settings
+const ColorblindKey = "colorblind"
+
+// Colorblind reports whether colorblind mode is on for a game, or for
+// everything else when gameID is empty
+func Colorblind(gameID string) bool {
+	c := Get()
+	if on, err := strconv.ParseBool(c.Games[gameID][ColorblindKey]); err == nil {
+		return on
+	}
+	return c.Colorblind != nil && *c.Colorblind
+}
+
+// Update changes the config file. Settings are changed in place, so the
+// rest of the file, including comments, is left as it was; settings
+// cleared by change are removed.
+func Update(change func(*Config)) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	c, doc, err := read()
+	if err != nil {
+		return err
+	}
+	var original yaml.Node
+	if err := original.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	change(&c)
+
+	var updated yaml.Node
+	if err := updated.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	merge(doc.Content[0], &updated)
+	prune(doc.Content[0], &original, &updated)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := storage.WriteFile(path, buf.Bytes()); err != nil {
+		return err
+	}
+	current = &c
+	return nil
+}
+
+// read parses the config file, returning the settings and the document
+// they came from
+func read() (Config, yaml.Node, error) {
+	var c Config
+	var doc yaml.Node
+
+	path, err := Path()
+	if err != nil {
+		return c, doc, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, doc, nil
+	}
+	if err != nil {
+		return c, doc, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return c, doc, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := doc.Decode(&c); err != nil {
+		return c, doc, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return c, doc, nil
+}
+
+// merge copies the keys of one mapping node into another, keeping the
+// comments and order of keys that already exist
+func merge(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+
+		found := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value != key.Value {
+				continue
+			}
+			found = true
+			existing := dst.Content[j+1]
+			switch {
+			case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+				merge(existing, value)
+			case existing.Kind == yaml.ScalarNode && existing.Value == value.Value:
+				// Unchanged, keep the way it was written
+			default:
+				value.HeadComment = existing.HeadComment
+				value.LineComment = existing.LineComment
+				value.FootComment = existing.FootComment
+				dst.Content[j+1] = value
+			}
+			break
+		}
+		if !found {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+}
+
+// prune removes the keys that were in the original settings but not in
+// the updated ones. Keys the settings don't know about are left alone.
+func prune(dst, original, updated *yaml.Node) {
+	for i := 0; i+1 < len(original.Content); i += 2 {
+		key := original.Content[i].Value
+		value := mappingValue(updated, key)
+		if value == nil {
+			removeKey(dst, key)
+			continue
+		}
+		if existing := mappingValue(dst, key); existing != nil &&
+			existing.Kind == yaml.MappingNode && original.Content[i+1].Kind == yaml.MappingNode {
+			prune(existing, original.Content[i+1], value)
+		}
+	}
+}
+
+// mappingValue returns the value of key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package core
+
+import "sync"
+
+// Event is something notable that happened in a game, e.g. lines cleared
+// in tetris. Games publish events as they play; other parts of the
+// arcade, such as achievements, subscribe to them.
+type Event struct {
+	Game  string // ID of the game that published the event
+	Name  string // e.g. "lines_cleared"
+	Value int    // e.g. the number of lines
+}
+
+var (
+	subscribersMu  sync.Mutex
+	subscribers    = map[int]func(Event){}
+	lastSubscriber int
+)
+
+// Subscribe calls handler with every published event until the returned
+// function is called
+func Subscribe(handler func(Event)) (unsubscribe func()) {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	lastSubscriber++
+	id := lastSubscriber
+	subscribers[id] = handler
+
+	return func() {
+		subscribersMu.Lock()
+		defer subscribersMu.Unlock()
+		delete(subscribers, id)
+	}
+}
+
+// Publish delivers an event to every subscriber, before returning
+func Publish(event Event) {
+	subscribersMu.Lock()
+	handlers := make([]func(Event), 0, len(subscribers))
+	for _, handler := range subscribers {
+		handlers = append(handlers, handler)
+	}
+	subscribersMu.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
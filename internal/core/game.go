@@ -0,0 +1,115 @@
+package core
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+// Game is a playable game hosted by the arcade. On top of being a Bubble
+// Tea model it lets the host pause it, inspect its progress and describe
+// its controls.
+type Game interface {
+	tea.Model
+
+	// Pause freezes the game until Resume is called. Games that cannot
+	// be paused in their current screen may ignore it.
+	Pause()
+	Resume()
+
+	// State reports the current progress of the game
+	State() State
+
+	// KeyBindings lists the game's own controls, without the host
+	// controls shared by every game
+	KeyBindings() []KeyBinding
+}
+
+// Saveable is implemented by games that can be left halfway through and
+// resumed later
+type Saveable interface {
+	// SaveState encodes the game's progress as JSON
+	SaveState() ([]byte, error)
+
+	// LoadState restores progress encoded by SaveState into a newly
+	// created game
+	LoadState(data []byte) error
+}
+
+// Driven is implemented by games whose rules run on an engine, so hosts
+// such as the bot runner can play them without pressing keys
+type Driven interface {
+	// Engine returns the game's engine, or nil while there is none,
+	// e.g. before a mode is chosen
+	Engine() engine.Engine
+}
+
+// State is a snapshot of a game's progress
+type State struct {
+	Mode    string // e.g. "levels" for snake, empty before a mode is chosen
+	Score   int
+	Paused  bool
+	Over    bool
+	Outcome Outcome // how the game ended, once Over is set
+	Winner  string  // display name of the winner, empty for draws and solo games
+	// Assisted is set when a bot, such as the snake autopilot, played
+	// part of the game. Assisted games are not scored.
+	Assisted bool
+
+	// Details are game-specific counts worth keeping in the game's
+	// history, e.g. "lines" cleared in tetris
+	Details map[string]int
+}
+
+// Outcome describes how a finished game ended for the first player
+type Outcome = engine.Outcome
+
+const (
+	OutcomeNone = engine.OutcomeNone
+	OutcomeWin  = engine.OutcomeWin
+	OutcomeLoss = engine.OutcomeLoss
+	OutcomeDraw = engine.OutcomeDraw
+)
+
+// KeyBinding documents a control
+type KeyBinding struct {
+	Keys string // e.g. "↑ ↓ ← →"
+	Help string // e.g. "move"
+}
+
+// HostKeyBindings are the controls every host provides on top of a game
+var HostKeyBindings = []KeyBinding{
+	{Keys: "P", Help: "pause"},
+	{Keys: "ESC", Help: "return to menu"},
+}
+
+// HelpLine renders key bindings, followed by the host controls, as a
+// single line of help text
+func HelpLine(bindings []KeyBinding) string {
+	var parts []string
+	for _, binding := range append(bindings, HostKeyBindings...) {
+		parts = append(parts, binding.Keys+" to "+binding.Help)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Options configures a new game. The accepted keys are described by the
+// game's GameInfo.Options.
+type Options map[string]string
+
+// Get returns the value of key, or def when it is not set
+func (o Options) Get(key, def string) string {
+	if value, ok := o[key]; ok && value != "" {
+		return value
+	}
+	return def
+}
+
+// OptionInfo documents a game option
+type OptionInfo struct {
+	Key     string
+	Values  []string // accepted values, empty for free-form values
+	Default string
+	Help    string
+}
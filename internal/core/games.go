@@ -1,48 +1,31 @@
 package core
 
 import (
+	"fmt"
+	"slices"
 	"sort"
-
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/jakmaz/arcade/internal/games/chess"
-	"github.com/jakmaz/arcade/internal/games/snake"
-	"github.com/jakmaz/arcade/internal/games/tetris"
-	"github.com/jakmaz/arcade/internal/games/tictactoe"
+	"strconv"
+	"strings"
 )
 
 type GameInfo struct {
 	ID          string
 	Name        string
 	Description string
-	NewModel    func() tea.Model
+	Options     []OptionInfo
+	New         func(Options) Game
 }
 
-// Simple, explicit game registry
-var Games = map[string]GameInfo{
-	"snake": {
-		ID:          "snake",
-		Name:        "Snake",
-		Description: "Classic Snake game",
-		NewModel:    func() tea.Model { return snake.New() },
-	},
-	"tetris": {
-		ID:          "tetris",
-		Name:        "Tetris",
-		Description: "Block puzzle game",
-		NewModel:    func() tea.Model { return tetris.New() },
-	},
-	"chess": {
-		ID:          "chess",
-		Name:        "Chess",
-		Description: "Strategic board game",
-		NewModel:    func() tea.Model { return chess.New() },
-	},
-	"tictactoe": {
-		ID:          "tictactoe",
-		Name:        "Tic-Tac-Toe",
-		Description: "Classic game of tic-tac-toe",
-		NewModel:    func() tea.Model { return tictactoe.New() },
-	},
+// Games is the registry of available games. Each game package adds
+// itself from an init function; importing internal/games pulls them all in.
+var Games = map[string]GameInfo{}
+
+// Register adds a game to the registry
+func Register(info GameInfo) {
+	if _, exists := Games[info.ID]; exists {
+		panic(fmt.Sprintf("game %q registered twice", info.ID))
+	}
+	Games[info.ID] = info
 }
 
 func AvailableGames() []GameInfo {
@@ -56,9 +39,48 @@ func AvailableGames() []GameInfo {
 	return games
 }
 
-func CreateGame(id string) tea.Model {
-	if game, exists := Games[id]; exists {
-		return game.NewModel()
+// CreateGame creates a new game after checking options against the ones
+// the game declares
+func CreateGame(id string, options Options) (Game, error) {
+	game, exists := Games[id]
+	if !exists {
+		return nil, fmt.Errorf("game '%s' not found", id)
+	}
+
+	for key, value := range options {
+		if key == SeedOption {
+			if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid seed '%s'", value)
+			}
+			continue
+		}
+
+		if err := game.CheckOption(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return game.New(options), nil
+}
+
+// CheckOption reports whether the game accepts an option
+func (g GameInfo) CheckOption(key, value string) error {
+	info, ok := g.option(key)
+	if !ok {
+		return fmt.Errorf("%s has no option '%s'", g.Name, key)
+	}
+	if len(info.Values) > 0 && !slices.Contains(info.Values, value) {
+		return fmt.Errorf("invalid value '%s' for %s option '%s' (expected %s)",
+			value, g.Name, key, strings.Join(info.Values, ", "))
 	}
 	return nil
 }
+
+func (g GameInfo) option(key string) (OptionInfo, bool) {
+	for _, option := range g.Options {
+		if option.Key == key {
+			return option, true
+		}
+	}
+	return OptionInfo{}, false
+}
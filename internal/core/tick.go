@@ -0,0 +1,38 @@
+package core
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+// TickMsg advances a game that moves on its own by one step. Hosts send
+// it at the interval the game asks for; games never schedule their own
+// timers, so a recorded game can be replayed tick for tick.
+type TickMsg struct{}
+
+// Ticker is implemented by games that move on their own, e.g. falling
+// blocks or a chess clock
+type Ticker interface {
+	// TickInterval is the time until the next TickMsg, 0 for none
+	TickInterval() time.Duration
+}
+
+// SeedOption is accepted by every game. It seeds the game's random
+// number generator, so the same inputs always play out the same way.
+const SeedOption = "seed"
+
+// NewSeed returns a random seed for SeedOption
+func NewSeed() string {
+	return strconv.FormatUint(rand.Uint64(), 10)
+}
+
+// Rand returns a random number generator seeded with SeedOption, or a
+// randomly seeded one when it is not set
+func (o Options) Rand() *rand.Rand {
+	seed, err := strconv.ParseUint(o.Get(SeedOption, ""), 10, 64)
+	if err != nil {
+		seed = rand.Uint64()
+	}
+	return rand.New(rand.NewPCG(seed, seed))
+}
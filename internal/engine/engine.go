@@ -0,0 +1,112 @@
+// Package engine describes games independently of how they are shown.
+// Each game package implements Engine with its rules and state only, so
+// the same engine can be driven by the terminal UI, bots or tests.
+package engine
+
+import (
+	"errors"
+	"time"
+)
+
+// Action is a move in a game's own notation, e.g. "e2e4" in chess or
+// "left" in tetris
+type Action string
+
+// ErrIllegal is returned by Step for actions the game does not accept in
+// its current state
+var ErrIllegal = errors.New("illegal action")
+
+// Engine holds the state of a game and the rules that change it
+type Engine interface {
+	// Step plays an action. Illegal actions return an error wrapping
+	// ErrIllegal and leave the game unchanged.
+	Step(action Action) error
+
+	// Legal lists the actions Step accepts in the current state
+	Legal() []Action
+
+	// Result reports the score and, once the game is over, how it ended
+	Result() Result
+}
+
+// Realtime is implemented by engines that also advance on their own,
+// e.g. the falling piece in tetris
+type Realtime interface {
+	Engine
+
+	// Tick advances the game by one step of its clock
+	Tick()
+
+	// Interval is the time between ticks at the game's current pace,
+	// or 0 when the game does not need ticking
+	Interval() time.Duration
+}
+
+// TurnBased is implemented by engines whose players take turns. Players
+// are numbered from 0, and Result's Outcome is about player 0.
+type TurnBased interface {
+	Engine
+
+	// PlayerCount is the number of players
+	PlayerCount() int
+
+	// ToMove returns the player Step plays for
+	ToMove() int
+
+	// PlayerName returns the name of a player in the game's terms, e.g.
+	// "White"
+	PlayerName(player int) string
+}
+
+// Multiplayer is implemented by real-time engines where several players
+// act at once
+type Multiplayer interface {
+	Engine
+
+	// PlayerCount is the number of players
+	PlayerCount() int
+
+	// PlayerName returns the name of a player, e.g. "Player 1"
+	PlayerName(player int) string
+
+	// LegalFor lists the actions of one player that Step accepts now
+	LegalFor(player int) []Action
+}
+
+// Solver is implemented by engines with a built-in computer player
+type Solver interface {
+	Engine
+
+	// BestMove returns the computer's choice for the player to move
+	BestMove() Action
+}
+
+// Result is the outcome of a game, or its progress while it is running
+type Result struct {
+	Over    bool
+	Score   int
+	Outcome Outcome // how the game ended, once Over is set
+	Winner  string  // name of the winning side, empty for draws and solo games
+}
+
+// Outcome describes how a finished game ended for the first player
+type Outcome int
+
+const (
+	OutcomeNone Outcome = iota // solo games that only keep a score
+	OutcomeWin
+	OutcomeLoss
+	OutcomeDraw
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeWin:
+		return "win"
+	case OutcomeLoss:
+		return "loss"
+	case OutcomeDraw:
+		return "draw"
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var fenLetters = map[PieceKind]byte{Pawn: 'p', Knight: 'n', Bishop: 'b', Rook: 'r', Queen: 'q', King: 'k'}
+
+// FEN returns the position in Forsyth-Edwards Notation
+func (p *Position) FEN() string {
+	var b strings.Builder
+	for y := range 8 {
+		if y > 0 {
+			b.WriteByte('/')
+		}
+		empty := 0
+		for x := range 8 {
+			piece := p.Board[y][x]
+			if piece.Kind == NoPiece {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			letter := fenLetters[piece.Kind]
+			if piece.Color == White {
+				letter -= 'a' - 'A'
+			}
+			b.WriteByte(letter)
+		}
+		if empty > 0 {
+			b.WriteString(strconv.Itoa(empty))
+		}
+	}
+
+	if p.ToMove == White {
+		b.WriteString(" w ")
+	} else {
+		b.WriteString(" b ")
+	}
+
+	castling := ""
+	for _, right := range []struct {
+		color, side int
+		letter      string
+	}{{0, 0, "K"}, {0, 1, "Q"}, {1, 0, "k"}, {1, 1, "q"}} {
+		if p.Castling[right.color][right.side] {
+			castling += right.letter
+		}
+	}
+	if castling == "" {
+		castling = "-"
+	}
+	b.WriteString(castling)
+
+	if p.HasEnPassant {
+		b.WriteString(" " + p.EnPassant.String())
+	} else {
+		b.WriteString(" -")
+	}
+	b.WriteString(" " + strconv.Itoa(p.HalfmoveClock) + " " + strconv.Itoa(p.FullmoveNumber))
+	return b.String()
+}
+
+// ParseFEN reads a position in Forsyth-Edwards Notation. The move clocks
+// may be left out.
+func ParseFEN(fen string) (Position, error) {
+	var p Position
+	fields := strings.Fields(fen)
+	if len(fields) != 4 && len(fields) != 6 {
+		return p, fmt.Errorf("invalid FEN %q: expected 6 fields", fen)
+	}
+
+	rows := strings.Split(fields[0], "/")
+	if len(rows) != 8 {
+		return p, fmt.Errorf("invalid FEN %q: expected 8 ranks", fen)
+	}
+	for y, row := range rows {
+		x := 0
+		for _, c := range row {
+			if c >= '1' && c <= '8' {
+				x += int(c - '0')
+				continue
+			}
+			piece, ok := fenPiece(byte(c))
+			if !ok || x >= 8 {
+				return p, fmt.Errorf("invalid FEN %q: bad rank %q", fen, row)
+			}
+			p.Board[y][x] = piece
+			x++
+		}
+		if x != 8 {
+			return p, fmt.Errorf("invalid FEN %q: bad rank %q", fen, row)
+		}
+	}
+
+	switch fields[1] {
+	case "w":
+		p.ToMove = White
+	case "b":
+		p.ToMove = Black
+	default:
+		return p, fmt.Errorf("invalid FEN %q: bad side to move %q", fen, fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			i := strings.IndexRune("KQkq", c)
+			if i < 0 {
+				return p, fmt.Errorf("invalid FEN %q: bad castling rights %q", fen, fields[2])
+			}
+			p.Castling[i/2][i%2] = true
+		}
+	}
+
+	if fields[3] != "-" {
+		s := fields[3]
+		if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+			return p, fmt.Errorf("invalid FEN %q: bad en passant square %q", fen, s)
+		}
+		p.EnPassant = Square{X: int(s[0] - 'a'), Y: int('8' - s[1])}
+		p.HasEnPassant = true
+	}
+
+	p.FullmoveNumber = 1
+	if len(fields) == 6 {
+		halfmoves, err1 := strconv.Atoi(fields[4])
+		fullmoves, err2 := strconv.Atoi(fields[5])
+		if err1 != nil || err2 != nil || halfmoves < 0 || fullmoves < 1 {
+			return p, fmt.Errorf("invalid FEN %q: bad move clocks", fen)
+		}
+		p.HalfmoveClock, p.FullmoveNumber = halfmoves, fullmoves
+	}
+	return p, nil
+}
+
+// fenPiece returns the piece a FEN letter stands for
+func fenPiece(letter byte) (Piece, bool) {
+	color := Black
+	if letter >= 'A' && letter <= 'Z' {
+		color = White
+		letter += 'a' - 'A'
+	}
+	for kind, l := range fenLetters {
+		if l == letter {
+			return Piece{Kind: kind, Color: color}, true
+		}
+	}
+	return Piece{}, false
+}
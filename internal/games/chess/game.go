@@ -0,0 +1,190 @@
+// Package chess is the chess engine. Actions are moves in UCI notation,
+// e.g. "e2e4", or "e7e8q" for a promotion.
+package chess
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+// ClockTick is how often the clock of a timed game runs down
+const ClockTick = 100 * time.Millisecond
+
+// Game is a game of chess from the starting position, optionally played
+// on a clock
+type Game struct {
+	position Position
+	moves    []Move
+	status   Status
+	clocks   [2]time.Duration // remaining time per color, unused without a clock
+	timed    bool
+	flagged  bool // the side to move ran out of time
+}
+
+var (
+	_ engine.Realtime  = (*Game)(nil)
+	_ engine.TurnBased = (*Game)(nil)
+)
+
+// NewGame starts a game giving each side clock to make all their moves,
+// or an untimed game when clock is 0
+func NewGame(clock time.Duration) *Game {
+	return &Game{
+		position: NewPosition(),
+		clocks:   [2]time.Duration{clock, clock},
+		timed:    clock > 0,
+	}
+}
+
+func (g *Game) Step(action engine.Action) error {
+	if g.Over() {
+		return fmt.Errorf("%w: the game is over", engine.ErrIllegal)
+	}
+	move, ok := g.position.ParseMove(string(action))
+	if !ok {
+		return fmt.Errorf("%w: %s", engine.ErrIllegal, action)
+	}
+	g.Play(move)
+	return nil
+}
+
+// Play makes a move, which must be one of the position's legal moves
+func (g *Game) Play(move Move) {
+	g.position = g.position.Apply(move)
+	g.moves = append(g.moves, move)
+	g.status = g.position.Status()
+}
+
+func (g *Game) Legal() []engine.Action {
+	if g.Over() {
+		return nil
+	}
+	var actions []engine.Action
+	for _, move := range g.position.LegalMoves() {
+		actions = append(actions, engine.Action(move.String()))
+	}
+	return actions
+}
+
+func (g *Game) Result() engine.Result {
+	result := engine.Result{Over: g.Over()}
+
+	switch {
+	case g.flagged || g.status == Checkmate:
+		// The side to move has lost
+		winner := g.position.ToMove.Opponent()
+		result.Winner = winner.String()
+		result.Outcome = engine.OutcomeWin
+		if winner == Black {
+			result.Outcome = engine.OutcomeLoss
+		}
+	case g.status != Ongoing:
+		result.Outcome = engine.OutcomeDraw
+	}
+	return result
+}
+
+// PlayerCount is two, White being player 0
+func (g *Game) PlayerCount() int { return 2 }
+
+func (g *Game) ToMove() int { return int(g.position.ToMove) }
+
+func (g *Game) PlayerName(player int) string { return Color(player).String() }
+
+// Tick runs down the clock of the side to move, which loses on time
+// when it reaches zero
+func (g *Game) Tick() {
+	if !g.timed || g.Over() {
+		return
+	}
+	toMove := g.position.ToMove
+	g.clocks[toMove] -= ClockTick
+	if g.clocks[toMove] <= 0 {
+		g.clocks[toMove] = 0
+		g.flagged = true
+	}
+}
+
+// Interval runs the clock, if the game has one
+func (g *Game) Interval() time.Duration {
+	if !g.timed || g.Over() {
+		return 0
+	}
+	return ClockTick
+}
+
+// Over reports whether the game has ended on the board or on time
+func (g *Game) Over() bool {
+	return g.flagged || g.status != Ongoing
+}
+
+// Position returns the current position
+func (g *Game) Position() Position { return g.position }
+
+// Moves returns the moves played so far
+func (g *Game) Moves() []Move { return g.moves }
+
+// Status reports whether the position ended the game
+func (g *Game) Status() Status { return g.status }
+
+// Flagged reports whether the side to move ran out of time
+func (g *Game) Flagged() bool { return g.flagged }
+
+// Timed reports whether the game is played on a clock
+func (g *Game) Timed() bool { return g.timed }
+
+// Clock returns the time a side has left
+func (g *Game) Clock(c Color) time.Duration { return g.clocks[c] }
+
+// savedGame is a game as encoded to JSON. The position is rebuilt by
+// replaying the moves, which keeps castling rights, en passant and the
+// move clocks exact. FEN is only written, for readers such as bots.
+type savedGame struct {
+	FEN    string            `json:"fen,omitempty"`
+	Moves  []string          `json:"moves"`            // UCI notation
+	Clocks *[2]time.Duration `json:"clocks,omitempty"` // timed games only
+}
+
+func (g *Game) MarshalJSON() ([]byte, error) {
+	state := savedGame{FEN: g.position.FEN()}
+	for _, move := range g.moves {
+		state.Moves = append(state.Moves, move.String())
+	}
+	if g.timed {
+		clocks := g.clocks
+		state.Clocks = &clocks
+	}
+	return json.Marshal(state)
+}
+
+// UnmarshalJSON restores a game into one created by NewGame, which
+// decides whether it is timed
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var state savedGame
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	position := NewPosition()
+	var moves []Move
+	for _, uci := range state.Moves {
+		move, ok := position.ParseMove(uci)
+		if !ok {
+			return fmt.Errorf("illegal move %s", uci)
+		}
+		position = position.Apply(move)
+		moves = append(moves, move)
+	}
+
+	g.position = position
+	g.moves = moves
+	g.status = position.Status()
+	if g.timed && state.Clocks != nil {
+		g.clocks = *state.Clocks
+		g.flagged = g.clocks[position.ToMove] <= 0
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+var sanLetters = map[PieceKind]string{Knight: "N", Bishop: "B", Rook: "R", Queen: "Q", King: "K"}
+
+// SAN returns a legal move in Standard Algebraic Notation, e.g. "Nxe5+"
+func (p *Position) SAN(m Move) string {
+	piece := p.At(m.From)
+
+	var s string
+	switch {
+	case piece.Kind == King && m.To.X-m.From.X == 2:
+		s = "O-O"
+	case piece.Kind == King && m.From.X-m.To.X == 2:
+		s = "O-O-O"
+	case piece.Kind == Pawn:
+		if m.From.X != m.To.X {
+			s = m.From.String()[:1] + "x"
+		}
+		s += m.To.String()
+		if m.Promotion != NoPiece {
+			s += "=" + sanLetters[m.Promotion]
+		}
+	default:
+		s = sanLetters[piece.Kind] + p.disambiguate(m)
+		if p.At(m.To).Kind != NoPiece {
+			s += "x"
+		}
+		s += m.To.String()
+	}
+
+	next := p.Apply(m)
+	switch {
+	case next.Status() == Checkmate:
+		s += "#"
+	case next.InCheck():
+		s += "+"
+	}
+	return s
+}
+
+// disambiguate returns the file, rank or square needed to tell m apart
+// from moves of another piece of the same kind to the same square
+func (p *Position) disambiguate(m Move) string {
+	piece := p.At(m.From)
+	sameFile, sameRank, ambiguous := false, false, false
+	for _, other := range p.LegalMoves() {
+		if other.To != m.To || other.From == m.From || p.At(other.From) != piece {
+			continue
+		}
+		ambiguous = true
+		sameFile = sameFile || other.From.X == m.From.X
+		sameRank = sameRank || other.From.Y == m.From.Y
+	}
+
+	from := m.From.String()
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return from[:1]
+	case !sameRank:
+		return from[1:]
+	}
+	return from
+}
+
+// Tag is a PGN header tag
+type Tag struct {
+	Name, Value string
+}
+
+// PGN writes the game in Portable Game Notation after the given tags,
+// which should start with the seven tag roster. A Result tag is added
+// from the game when missing; passing one records results decided off
+// the board, such as forfeits.
+func (g *Game) PGN(tags ...Tag) string {
+	result := ""
+	for _, tag := range tags {
+		if tag.Name == "Result" {
+			result = tag.Value
+		}
+	}
+	if result == "" {
+		result = g.pgnResult()
+		tags = append(tags, Tag{"Result", result})
+	}
+
+	var b strings.Builder
+	for _, tag := range tags {
+		value := strings.ReplaceAll(strings.ReplaceAll(tag.Value, `\`, `\\`), `"`, `\"`)
+		fmt.Fprintf(&b, "[%s \"%s\"]\n", tag.Name, value)
+	}
+	b.WriteString("\n")
+
+	// Wrap the move text below 80 columns
+	position := NewPosition()
+	line := 0
+	write := func(token string) {
+		if line > 0 && line+1+len(token) > 79 {
+			b.WriteString("\n")
+			line = 0
+		} else if line > 0 {
+			b.WriteString(" ")
+			line++
+		}
+		b.WriteString(token)
+		line += len(token)
+	}
+	for i, move := range g.moves {
+		if i%2 == 0 {
+			write(fmt.Sprintf("%d.", i/2+1))
+		}
+		write(position.SAN(move))
+		position = position.Apply(move)
+	}
+	write(result)
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (g *Game) pgnResult() string {
+	result := g.Result()
+	switch {
+	case !result.Over:
+		return "*"
+	case result.Winner == White.String():
+		return "1-0"
+	case result.Winner == Black.String():
+		return "0-1"
+	}
+	return "1/2-1/2"
+}
@@ -0,0 +1,485 @@
+package chess
+
+// Color is a side in the game
+type Color int
+
+const (
+	White Color = iota
+	Black
+)
+
+func (c Color) String() string {
+	if c == White {
+		return "White"
+	}
+	return "Black"
+}
+
+func (c Color) Opponent() Color {
+	return 1 - c
+}
+
+// PieceKind is the type of a chess piece
+type PieceKind int
+
+const (
+	NoPiece PieceKind = iota
+	Pawn
+	Knight
+	Bishop
+	Rook
+	Queen
+	King
+)
+
+// Piece is a colored piece, the zero value is an empty square
+type Piece struct {
+	Kind  PieceKind
+	Color Color
+}
+
+// Square is a board coordinate. X runs from the a-file (0) to the h-file
+// (7) and Y from the eighth rank (0) down to the first rank (7), matching
+// the order rows are drawn on screen.
+type Square struct {
+	X, Y int
+}
+
+func (s Square) valid() bool {
+	return s.X >= 0 && s.X < 8 && s.Y >= 0 && s.Y < 8
+}
+
+func (s Square) String() string {
+	return string(rune('a'+s.X)) + string(rune('8'-s.Y))
+}
+
+// Move is a move from one square to another. Promotion is the piece a
+// pawn turns into when it reaches the last rank.
+type Move struct {
+	From, To  Square
+	Promotion PieceKind
+}
+
+// String returns the move in UCI notation, e.g. "e2e4" or "e7e8q"
+func (m Move) String() string {
+	s := m.From.String() + m.To.String()
+	switch m.Promotion {
+	case Queen:
+		s += "q"
+	case Rook:
+		s += "r"
+	case Bishop:
+		s += "b"
+	case Knight:
+		s += "n"
+	}
+	return s
+}
+
+// Position is the complete state needed to continue a game
+type Position struct {
+	Board          [8][8]Piece // indexed [y][x]
+	ToMove         Color
+	Castling       [2][2]bool // [color][kingside, queenside]
+	EnPassant      Square     // square a pawn can capture onto en passant
+	HasEnPassant   bool
+	HalfmoveClock  int // moves since the last capture or pawn move
+	FullmoveNumber int
+}
+
+// Status describes whether a position ends the game
+type Status int
+
+const (
+	Ongoing Status = iota
+	Checkmate
+	Stalemate
+	InsufficientMaterial
+	FiftyMoveRule
+)
+
+func (s Status) String() string {
+	switch s {
+	case Checkmate:
+		return "checkmate"
+	case Stalemate:
+		return "stalemate"
+	case InsufficientMaterial:
+		return "insufficient material"
+	case FiftyMoveRule:
+		return "fifty-move rule"
+	}
+	return "ongoing"
+}
+
+var (
+	knightOffsets = [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+	kingOffsets   = [][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	bishopDirs    = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+	rookDirs      = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	promotions    = []PieceKind{Queen, Rook, Bishop, Knight}
+)
+
+// NewPosition returns the standard starting position
+func NewPosition() Position {
+	var p Position
+	backRank := []PieceKind{Rook, Knight, Bishop, Queen, King, Bishop, Knight, Rook}
+	for x, kind := range backRank {
+		p.Board[0][x] = Piece{kind, Black}
+		p.Board[1][x] = Piece{Pawn, Black}
+		p.Board[6][x] = Piece{Pawn, White}
+		p.Board[7][x] = Piece{kind, White}
+	}
+	p.Castling = [2][2]bool{{true, true}, {true, true}}
+	p.FullmoveNumber = 1
+	return p
+}
+
+// At returns the piece on s
+func (p *Position) At(s Square) Piece {
+	return p.Board[s.Y][s.X]
+}
+
+// homeRow is the back rank of a color
+func homeRow(c Color) int {
+	if c == White {
+		return 7
+	}
+	return 0
+}
+
+// forward is the direction pawns of a color move along Y
+func forward(c Color) int {
+	if c == White {
+		return -1
+	}
+	return 1
+}
+
+// LegalMoves returns every legal move for the side to move
+func (p *Position) LegalMoves() []Move {
+	var legal []Move
+	for _, move := range p.pseudoLegalMoves() {
+		next := p.Apply(move)
+		if !next.kingAttacked(p.ToMove) {
+			legal = append(legal, move)
+		}
+	}
+	return legal
+}
+
+// ParseMove finds the legal move written in UCI notation, e.g. "e2e4"
+func (p *Position) ParseMove(uci string) (Move, bool) {
+	for _, move := range p.LegalMoves() {
+		if move.String() == uci {
+			return move, true
+		}
+	}
+	return Move{}, false
+}
+
+// LegalMovesFrom returns the legal moves of the piece on s
+func (p *Position) LegalMovesFrom(s Square) []Move {
+	var moves []Move
+	for _, move := range p.LegalMoves() {
+		if move.From == s {
+			moves = append(moves, move)
+		}
+	}
+	return moves
+}
+
+func (p *Position) pseudoLegalMoves() []Move {
+	var moves []Move
+	for y := range 8 {
+		for x := range 8 {
+			piece := p.Board[y][x]
+			if piece.Kind == NoPiece || piece.Color != p.ToMove {
+				continue
+			}
+			from := Square{x, y}
+
+			switch piece.Kind {
+			case Pawn:
+				moves = p.appendPawnMoves(moves, from)
+			case Knight:
+				moves = p.appendSteps(moves, from, knightOffsets)
+			case Bishop:
+				moves = p.appendSlides(moves, from, bishopDirs)
+			case Rook:
+				moves = p.appendSlides(moves, from, rookDirs)
+			case Queen:
+				moves = p.appendSlides(moves, from, bishopDirs)
+				moves = p.appendSlides(moves, from, rookDirs)
+			case King:
+				moves = p.appendSteps(moves, from, kingOffsets)
+				moves = p.appendCastling(moves, from)
+			}
+		}
+	}
+	return moves
+}
+
+func (p *Position) appendPawnMoves(moves []Move, from Square) []Move {
+	color := p.At(from).Color
+	dir := forward(color)
+	lastRow := homeRow(color.Opponent())
+	startRow := homeRow(color) + dir
+
+	add := func(to Square) {
+		if to.Y == lastRow {
+			for _, kind := range promotions {
+				moves = append(moves, Move{From: from, To: to, Promotion: kind})
+			}
+			return
+		}
+		moves = append(moves, Move{From: from, To: to})
+	}
+
+	one := Square{from.X, from.Y + dir}
+	if one.valid() && p.At(one).Kind == NoPiece {
+		add(one)
+		two := Square{from.X, from.Y + 2*dir}
+		if from.Y == startRow && p.At(two).Kind == NoPiece {
+			add(two)
+		}
+	}
+
+	for _, dx := range []int{-1, 1} {
+		to := Square{from.X + dx, from.Y + dir}
+		if !to.valid() {
+			continue
+		}
+		target := p.At(to)
+		if target.Kind != NoPiece && target.Color != color {
+			add(to)
+		} else if p.HasEnPassant && to == p.EnPassant {
+			add(to)
+		}
+	}
+
+	return moves
+}
+
+func (p *Position) appendSteps(moves []Move, from Square, offsets [][2]int) []Move {
+	color := p.At(from).Color
+	for _, offset := range offsets {
+		to := Square{from.X + offset[0], from.Y + offset[1]}
+		if !to.valid() {
+			continue
+		}
+		if target := p.At(to); target.Kind == NoPiece || target.Color != color {
+			moves = append(moves, Move{From: from, To: to})
+		}
+	}
+	return moves
+}
+
+func (p *Position) appendSlides(moves []Move, from Square, dirs [][2]int) []Move {
+	color := p.At(from).Color
+	for _, dir := range dirs {
+		to := Square{from.X + dir[0], from.Y + dir[1]}
+		for to.valid() {
+			target := p.At(to)
+			if target.Kind != NoPiece {
+				if target.Color != color {
+					moves = append(moves, Move{From: from, To: to})
+				}
+				break
+			}
+			moves = append(moves, Move{From: from, To: to})
+			to = Square{to.X + dir[0], to.Y + dir[1]}
+		}
+	}
+	return moves
+}
+
+func (p *Position) appendCastling(moves []Move, from Square) []Move {
+	color := p.At(from).Color
+	row := homeRow(color)
+	if from != (Square{4, row}) || p.squareAttacked(from, color.Opponent()) {
+		return moves
+	}
+
+	// Kingside: f and g files empty and safe
+	if p.Castling[color][0] &&
+		p.Board[row][5].Kind == NoPiece && p.Board[row][6].Kind == NoPiece &&
+		!p.squareAttacked(Square{5, row}, color.Opponent()) &&
+		!p.squareAttacked(Square{6, row}, color.Opponent()) {
+		moves = append(moves, Move{From: from, To: Square{6, row}})
+	}
+
+	// Queenside: b, c and d files empty, c and d safe
+	if p.Castling[color][1] &&
+		p.Board[row][1].Kind == NoPiece && p.Board[row][2].Kind == NoPiece && p.Board[row][3].Kind == NoPiece &&
+		!p.squareAttacked(Square{3, row}, color.Opponent()) &&
+		!p.squareAttacked(Square{2, row}, color.Opponent()) {
+		moves = append(moves, Move{From: from, To: Square{2, row}})
+	}
+
+	return moves
+}
+
+// Apply returns the position after a move. The move is assumed to be
+// pseudo-legal.
+func (p *Position) Apply(m Move) Position {
+	next := *p
+	piece := next.At(m.From)
+	captured := next.At(m.To)
+
+	next.Board[m.From.Y][m.From.X] = Piece{}
+	next.Board[m.To.Y][m.To.X] = piece
+
+	switch piece.Kind {
+	case Pawn:
+		// En passant removes the pawn beside the destination
+		if p.HasEnPassant && m.To == p.EnPassant && captured.Kind == NoPiece {
+			next.Board[m.From.Y][m.To.X] = Piece{}
+			captured = Piece{Kind: Pawn}
+		}
+		if m.Promotion != NoPiece {
+			next.Board[m.To.Y][m.To.X] = Piece{m.Promotion, piece.Color}
+		}
+	case King:
+		next.Castling[piece.Color] = [2]bool{false, false}
+		// Castling moves the rook over the king
+		if m.To.X-m.From.X == 2 {
+			next.Board[m.To.Y][5] = next.Board[m.To.Y][7]
+			next.Board[m.To.Y][7] = Piece{}
+		} else if m.From.X-m.To.X == 2 {
+			next.Board[m.To.Y][3] = next.Board[m.To.Y][0]
+			next.Board[m.To.Y][0] = Piece{}
+		}
+	}
+
+	// Moving or capturing a rook on its corner removes that castling right
+	for _, color := range []Color{White, Black} {
+		row := homeRow(color)
+		for _, s := range []Square{m.From, m.To} {
+			if s == (Square{7, row}) {
+				next.Castling[color][0] = false
+			}
+			if s == (Square{0, row}) {
+				next.Castling[color][1] = false
+			}
+		}
+	}
+
+	next.HasEnPassant = false
+	if piece.Kind == Pawn && (m.To.Y-m.From.Y == 2 || m.From.Y-m.To.Y == 2) {
+		next.EnPassant = Square{m.From.X, (m.From.Y + m.To.Y) / 2}
+		next.HasEnPassant = true
+	}
+
+	if piece.Kind == Pawn || captured.Kind != NoPiece {
+		next.HalfmoveClock = 0
+	} else {
+		next.HalfmoveClock++
+	}
+	if p.ToMove == Black {
+		next.FullmoveNumber++
+	}
+	next.ToMove = p.ToMove.Opponent()
+
+	return next
+}
+
+// InCheck reports whether the side to move is in check
+func (p *Position) InCheck() bool {
+	return p.kingAttacked(p.ToMove)
+}
+
+func (p *Position) kingAttacked(color Color) bool {
+	for y := range 8 {
+		for x := range 8 {
+			if p.Board[y][x] == (Piece{King, color}) {
+				return p.squareAttacked(Square{x, y}, color.Opponent())
+			}
+		}
+	}
+	return false
+}
+
+// squareAttacked reports whether any piece of color by attacks s
+func (p *Position) squareAttacked(s Square, by Color) bool {
+	// Pawns attack diagonally forward, so look backwards from s
+	for _, dx := range []int{-1, 1} {
+		from := Square{s.X + dx, s.Y - forward(by)}
+		if from.valid() && p.At(from) == (Piece{Pawn, by}) {
+			return true
+		}
+	}
+
+	for _, offset := range knightOffsets {
+		from := Square{s.X + offset[0], s.Y + offset[1]}
+		if from.valid() && p.At(from) == (Piece{Knight, by}) {
+			return true
+		}
+	}
+
+	for _, offset := range kingOffsets {
+		from := Square{s.X + offset[0], s.Y + offset[1]}
+		if from.valid() && p.At(from) == (Piece{King, by}) {
+			return true
+		}
+	}
+
+	slides := []struct {
+		dirs  [][2]int
+		kinds [2]PieceKind
+	}{
+		{bishopDirs, [2]PieceKind{Bishop, Queen}},
+		{rookDirs, [2]PieceKind{Rook, Queen}},
+	}
+	for _, slide := range slides {
+		for _, dir := range slide.dirs {
+			from := Square{s.X + dir[0], s.Y + dir[1]}
+			for from.valid() {
+				piece := p.At(from)
+				if piece.Kind != NoPiece {
+					if piece.Color == by && (piece.Kind == slide.kinds[0] || piece.Kind == slide.kinds[1]) {
+						return true
+					}
+					break
+				}
+				from = Square{from.X + dir[0], from.Y + dir[1]}
+			}
+		}
+	}
+
+	return false
+}
+
+// Status reports whether the game is over in this position
+func (p *Position) Status() Status {
+	if len(p.LegalMoves()) == 0 {
+		if p.InCheck() {
+			return Checkmate
+		}
+		return Stalemate
+	}
+	if p.insufficientMaterial() {
+		return InsufficientMaterial
+	}
+	if p.HalfmoveClock >= 100 {
+		return FiftyMoveRule
+	}
+	return Ongoing
+}
+
+// insufficientMaterial reports positions where neither side can mate:
+// bare kings, or a king with a single minor piece against a bare king
+func (p *Position) insufficientMaterial() bool {
+	minors := 0
+	for y := range 8 {
+		for x := range 8 {
+			switch p.Board[y][x].Kind {
+			case Pawn, Rook, Queen:
+				return false
+			case Bishop, Knight:
+				minors++
+			}
+		}
+	}
+	return minors <= 1
+}
@@ -0,0 +1,300 @@
+package chess
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func perft(p Position, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	moves := p.LegalMoves()
+	if depth == 1 {
+		return len(moves)
+	}
+	nodes := 0
+	for _, move := range moves {
+		nodes += perft(p.Apply(move), depth-1)
+	}
+	return nodes
+}
+
+func mustParseFEN(t *testing.T, fen string) Position {
+	t.Helper()
+	p, err := ParseFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Move counts from the Chess Programming Wiki's perft results
+func TestPerft(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		nodes []int // by depth, from 1
+	}{
+		{"start", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", []int{20, 400, 8902, 197281}},
+		{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", []int{48, 2039, 97862}},
+		{"en passant and pins", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", []int{14, 191, 2812, 43238}},
+		{"promotions", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", []int{6, 264, 9467}},
+		{"promotion captures", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", []int{44, 1486, 62379}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := mustParseFEN(t, tt.fen)
+			for i, want := range tt.nodes {
+				depth := i + 1
+				if depth == 4 && testing.Short() {
+					break
+				}
+				if got := perft(p, depth); got != want {
+					t.Errorf("perft(%d) = %d, want %d", depth, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFENRoundTrip(t *testing.T) {
+	fens := []string{
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+		"4k3/8/8/8/8/8/8/4K2R b Kq - 37 60",
+	}
+	for _, fen := range fens {
+		p := mustParseFEN(t, fen)
+		if got := p.FEN(); got != fen {
+			t.Errorf("FEN() = %q, want %q", got, fen)
+		}
+	}
+}
+
+func TestFENAfterMoves(t *testing.T) {
+	p := NewPosition()
+	for _, uci := range []string{"e2e4", "c7c5", "g1f3"} {
+		move, ok := p.ParseMove(uci)
+		if !ok {
+			t.Fatalf("%s is not legal", uci)
+		}
+		p = p.Apply(move)
+	}
+	want := "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"
+	if got := p.FEN(); got != want {
+		t.Errorf("FEN() = %q, want %q", got, want)
+	}
+}
+
+func TestParseFENErrors(t *testing.T) {
+	fens := []string{
+		"",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/9/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/ppppxppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KX - 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq e9 0 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - x 1",
+	}
+	for _, fen := range fens {
+		if _, err := ParseFEN(fen); err == nil {
+			t.Errorf("ParseFEN(%q) succeeded", fen)
+		}
+	}
+}
+
+// apply plays moves given in UCI notation, failing on illegal ones
+func apply(t *testing.T, p Position, moves ...string) Position {
+	t.Helper()
+	for _, uci := range moves {
+		move, ok := p.ParseMove(uci)
+		if !ok {
+			t.Fatalf("%s is not legal in %s", uci, p.FEN())
+		}
+		p = p.Apply(move)
+	}
+	return p
+}
+
+func legal(p Position, uci string) bool {
+	_, ok := p.ParseMove(uci)
+	return ok
+}
+
+func TestCastling(t *testing.T) {
+	tests := []struct {
+		name  string
+		fen   string
+		moves map[string]bool
+	}{
+		{"both sides", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1",
+			map[string]bool{"e1g1": true, "e1c1": true}},
+		{"no rights", "r3k2r/8/8/8/8/8/8/R3K2R w kq - 0 1",
+			map[string]bool{"e1g1": false, "e1c1": false}},
+		{"out of check", "4k3/8/8/8/8/8/4r3/R3K2R w KQ - 0 1",
+			map[string]bool{"e1g1": false, "e1c1": false}},
+		{"through an attacked square", "4k3/8/8/8/8/8/5r2/R3K2R w KQ - 0 1",
+			map[string]bool{"e1g1": false, "e1c1": true}},
+		{"rook attacked on b1", "1r2k3/8/8/8/8/8/8/R3K2R w KQ - 0 1",
+			map[string]bool{"e1g1": true, "e1c1": true}},
+		{"blocked", "4k3/8/8/8/8/8/8/RN2K1NR w KQ - 0 1",
+			map[string]bool{"e1g1": false, "e1c1": false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := mustParseFEN(t, tt.fen)
+			for move, want := range tt.moves {
+				if got := legal(p, move); got != want {
+					t.Errorf("%s legal = %v, want %v", move, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCastlingMovesRook(t *testing.T) {
+	p := mustParseFEN(t, "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1")
+	p = apply(t, p, "e1g1", "e8c8")
+	if got, want := p.FEN(), "2kr3r/8/8/8/8/8/8/R4RK1 w - - 2 2"; got != want {
+		t.Errorf("FEN() = %q, want %q", got, want)
+	}
+}
+
+func TestCastlingRightsLost(t *testing.T) {
+	tests := []struct {
+		moves []string
+		want  string
+	}{
+		{[]string{"h1h2"}, "Qkq"},
+		{[]string{"a1a2"}, "Kkq"},
+		{[]string{"e1e2"}, "kq"},
+		{[]string{"a1a8"}, "Kk"}, // captures the rook on a8
+		{[]string{"h1h8"}, "Qq"}, // captures the rook on h8
+		{[]string{"e1f1", "e8d8", "f1e1"}, "-"},
+	}
+	for _, tt := range tests {
+		p := apply(t, mustParseFEN(t, "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"), tt.moves...)
+		if got := strings.Fields(p.FEN())[2]; got != tt.want {
+			t.Errorf("after %v castling rights = %q, want %q", tt.moves, got, tt.want)
+		}
+	}
+}
+
+func TestEnPassant(t *testing.T) {
+	p := apply(t, NewPosition(), "e2e4", "a7a6", "e4e5", "d7d5")
+	if !legal(p, "e5d6") {
+		t.Fatal("e5d6 en passant is not legal")
+	}
+	p = apply(t, p, "e5d6")
+	if got := p.At(Square{X: 3, Y: 3}); got.Kind != NoPiece {
+		t.Errorf("captured pawn on d5 is still there: %v", got)
+	}
+	if got := p.At(Square{X: 3, Y: 2}); got != (Piece{Pawn, White}) {
+		t.Errorf("d6 = %v, want a white pawn", got)
+	}
+
+	// The right lapses after one move
+	p = apply(t, NewPosition(), "e2e4", "a7a6", "e4e5", "d7d5", "h2h3", "a6a5")
+	if legal(p, "e5d6") {
+		t.Error("e5d6 en passant is legal a move too late")
+	}
+
+	// Capturing en passant may not expose the king
+	p = mustParseFEN(t, "8/8/8/K2pP2r/8/8/8/7k w - d6 0 1")
+	if legal(p, "e5d6") {
+		t.Error("e5d6 en passant leaves the king in check")
+	}
+}
+
+func TestPromotion(t *testing.T) {
+	p := mustParseFEN(t, "1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1")
+	var promotions []string
+	for _, move := range p.LegalMoves() {
+		if move.From == (Square{X: 0, Y: 1}) {
+			promotions = append(promotions, move.String())
+		}
+	}
+	slices.Sort(promotions)
+	want := []string{"a7a8b", "a7a8n", "a7a8q", "a7a8r", "a7b8b", "a7b8n", "a7b8q", "a7b8r"}
+	if !slices.Equal(promotions, want) {
+		t.Errorf("pawn moves = %v, want %v", promotions, want)
+	}
+	if legal(p, "a7a8") {
+		t.Error("a7a8 without a promotion is legal")
+	}
+
+	p = apply(t, p, "a7b8n")
+	if got := p.At(Square{X: 1, Y: 0}); got != (Piece{Knight, White}) {
+		t.Errorf("b8 = %v, want a white knight", got)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		want Status
+	}{
+		{"start", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", Ongoing},
+		{"fool's mate", "rnb1kbnr/pppp1ppp/8/4p3/6Pq/5P2/PPPPP2P/RNBQKBNR w KQkq - 1 3", Checkmate},
+		{"stalemate", "7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", Stalemate},
+		{"bare kings", "8/8/4k3/8/8/4K3/8/8 w - - 0 1", InsufficientMaterial},
+		{"king and bishop", "8/8/4k3/8/8/4KB2/8/8 w - - 0 1", InsufficientMaterial},
+		{"king and rook", "8/8/4k3/8/8/4KR2/8/8 w - - 0 1", Ongoing},
+		{"fifty moves", "8/8/4k3/8/8/4KR2/8/8 w - - 100 80", FiftyMoveRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := mustParseFEN(t, tt.fen)
+			if got := p.Status(); got != tt.want {
+				t.Errorf("Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameJSON(t *testing.T) {
+	g := NewGame(0)
+	for _, uci := range []string{"e2e4", "e7e5", "g1f3"} {
+		position := g.Position()
+		move, _ := position.ParseMove(uci)
+		g.Play(move)
+	}
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "clocks") {
+		t.Errorf("untimed game saved with clocks: %s", data)
+	}
+
+	restored := NewGame(0)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	got, want := restored.Position(), g.Position()
+	if got.FEN() != want.FEN() {
+		t.Errorf("restored position %q, want %q", got.FEN(), want.FEN())
+	}
+
+	timed := NewGame(5 * time.Minute)
+	data, err = json.Marshal(timed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored = NewGame(5 * time.Minute)
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Clock(White); got != 5*time.Minute {
+		t.Errorf("restored clock = %v, want 5m", got)
+	}
+}
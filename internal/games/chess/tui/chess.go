@@ -0,0 +1,258 @@
+// Package tui plays the chess engine in the terminal
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/games/chess"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+func init() {
+	core.Register(core.GameInfo{
+		ID:          "chess",
+		Name:        "Chess",
+		Description: "Strategic board game",
+		Options: []core.OptionInfo{
+			{Key: "clock", Values: []string{"0", "1", "3", "5", "10", "15", "30"}, Default: "0", Help: "minutes per side, 0 for no clock"},
+		},
+		New: func(options core.Options) core.Game { return New(options) },
+	})
+}
+
+type Model struct {
+	game             *chess.Game
+	cursorX, cursorY int
+	selected         *chess.Square
+	targets          []chess.Move
+	paused           bool
+	width, height    int
+}
+
+func New(options core.Options) *Model {
+	minutes, _ := strconv.Atoi(options.Get("clock", "0"))
+
+	return &Model{
+		game:    chess.NewGame(time.Duration(minutes) * time.Minute),
+		cursorX: 4,
+		cursorY: 6,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// TickInterval runs the clock, if the game has one
+func (m *Model) TickInterval() time.Duration {
+	return m.game.Interval()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case core.TickMsg:
+		if !m.paused {
+			m.game.Tick()
+		}
+
+	case tea.KeyMsg:
+		if m.paused || m.game.Over() {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "up":
+			m.cursorY = max(m.cursorY-1, 0)
+		case "down":
+			m.cursorY = min(m.cursorY+1, 7)
+		case "left":
+			m.cursorX = max(m.cursorX-1, 0)
+		case "right":
+			m.cursorX = min(m.cursorX+1, 7)
+		case "enter", " ":
+			m.selectSquare(chess.Square{X: m.cursorX, Y: m.cursorY})
+		}
+	}
+	return m, nil
+}
+
+// selectSquare picks up a piece of the side to move, or moves the piece
+// already picked up to the cursor
+func (m *Model) selectSquare(s chess.Square) {
+	if m.selected != nil {
+		for _, move := range m.targets {
+			if move.To == s {
+				// Pawns reaching the last rank always become queens
+				if move.Promotion != chess.NoPiece {
+					move.Promotion = chess.Queen
+				}
+				m.play(move)
+				return
+			}
+		}
+	}
+
+	position := m.game.Position()
+	piece := position.At(s)
+	if piece.Kind == chess.NoPiece || piece.Color != position.ToMove || (m.selected != nil && *m.selected == s) {
+		m.selected = nil
+		m.targets = nil
+		return
+	}
+
+	m.selected = &s
+	m.targets = position.LegalMovesFrom(s)
+}
+
+func (m *Model) play(move chess.Move) {
+	m.game.Play(move)
+	m.selected = nil
+	m.targets = nil
+
+	if m.game.Status() == chess.Checkmate {
+		core.Publish(core.Event{Game: "chess", Name: "checkmate", Value: len(m.game.Moves())})
+	}
+}
+
+func (m *Model) Pause()  { m.paused = true }
+func (m *Model) Resume() { m.paused = false }
+
+func (m *Model) Engine() engine.Engine {
+	return m.game
+}
+
+func (m *Model) State() core.State {
+	result := m.game.Result()
+	state := core.State{
+		Paused:  m.paused,
+		Over:    result.Over,
+		Outcome: result.Outcome,
+		Winner:  result.Winner,
+		Mode:    "untimed",
+	}
+	if m.game.Timed() {
+		state.Mode = "timed"
+	}
+	return state
+}
+
+func (m *Model) KeyBindings() []core.KeyBinding {
+	return []core.KeyBinding{
+		{Keys: "↑ ↓ ← →", Help: "move"},
+		{Keys: "Enter", Help: "select"},
+	}
+}
+
+func (m *Model) View() string {
+	title := styles.TitleStyle.Render("Chess")
+
+	board := m.renderBoard()
+
+	position := m.game.Position()
+	var status string
+	switch {
+	case m.game.Flagged():
+		status = fmt.Sprintf("%s ran out of time - %s wins", position.ToMove, position.ToMove.Opponent())
+	case m.game.Status() == chess.Checkmate:
+		status = fmt.Sprintf("Checkmate - %s wins", position.ToMove.Opponent())
+	case m.game.Status() != chess.Ongoing:
+		status = "Draw by " + m.game.Status().String()
+	case position.InCheck():
+		status = "Current Player: " + position.ToMove.String() + " (check)"
+	default:
+		status = "Current Player: " + position.ToMove.String()
+	}
+	currentPlayer := styles.SelectedItemStyle.Render(status)
+
+	sections := []string{title, "", board, "", currentPlayer}
+	if m.game.Timed() {
+		sections = append(sections, m.renderClocks())
+	}
+
+	help := styles.HelpStyle.Render(core.HelpLine(m.KeyBindings()))
+	sections = append(sections, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Center, sections...)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m *Model) renderClocks() string {
+	render := func(c chess.Color) string {
+		remaining := m.game.Clock(c)
+		text := fmt.Sprintf("%s %d:%02d", c, int(remaining.Minutes()), int(remaining.Seconds())%60)
+		if c == m.game.Position().ToMove && !m.game.Over() {
+			return styles.SelectedItemStyle.Render(text)
+		}
+		return styles.MenuItemStyle.Render(text)
+	}
+	return render(chess.White) + "   " + render(chess.Black)
+}
+
+var glyphs = map[chess.Piece]string{
+	{Kind: chess.King, Color: chess.White}: "♔", {Kind: chess.Queen, Color: chess.White}: "♕",
+	{Kind: chess.Rook, Color: chess.White}: "♖", {Kind: chess.Bishop, Color: chess.White}: "♗",
+	{Kind: chess.Knight, Color: chess.White}: "♘", {Kind: chess.Pawn, Color: chess.White}: "♙",
+	{Kind: chess.King, Color: chess.Black}: "♚", {Kind: chess.Queen, Color: chess.Black}: "♛",
+	{Kind: chess.Rook, Color: chess.Black}: "♜", {Kind: chess.Bishop, Color: chess.Black}: "♝",
+	{Kind: chess.Knight, Color: chess.Black}: "♞", {Kind: chess.Pawn, Color: chess.Black}: "♟",
+}
+
+func (m *Model) renderBoard() string {
+	position := m.game.Position()
+	targets := make(map[chess.Square]bool, len(m.targets))
+	for _, move := range m.targets {
+		targets[move.To] = true
+	}
+
+	var rows []string
+
+	for y := range 8 {
+		var cells []string
+		for x := range 8 {
+			square := chess.Square{X: x, Y: y}
+			piece := position.Board[y][x]
+			cellContent := " "
+
+			if piece.Kind != chess.NoPiece {
+				if piece.Color == chess.White {
+					cellContent = styles.WhitePieceStyle.Render(glyphs[piece])
+				} else {
+					cellContent = styles.BlackPieceStyle.Render(glyphs[piece])
+				}
+			} else if targets[square] {
+				cellContent = styles.SelectedItemStyle.Render("·")
+			}
+
+			style := styles.CellStyle
+			if (x+y)%2 == 1 {
+				style = style.Background(styles.ChessDarkSquare)
+			}
+			if targets[square] && piece.Kind != chess.NoPiece {
+				style = style.BorderForeground(styles.WarningStyle.GetForeground())
+			}
+			if m.selected != nil && *m.selected == square {
+				style = style.BorderForeground(styles.SelectedItemStyle.GetForeground())
+			}
+			if m.cursorX == x && m.cursorY == y {
+				style = styles.SelectedCellStyle
+			}
+
+			cells = append(cells, style.Render(cellContent))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+
+	return strings.Join(rows, "\n")
+}
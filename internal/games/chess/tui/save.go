@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jakmaz/arcade/internal/games/chess"
+)
+
+// savedState is a game in progress as written to a save file. Whether
+// the game is timed comes from the game's options.
+type savedState struct {
+	Game    *chess.Game `json:"game"`
+	CursorX int         `json:"cursor_x"`
+	CursorY int         `json:"cursor_y"`
+}
+
+func (m *Model) SaveState() ([]byte, error) {
+	return json.Marshal(savedState{
+		Game:    m.game,
+		CursorX: m.cursorX,
+		CursorY: m.cursorY,
+	})
+}
+
+func (m *Model) LoadState(data []byte) error {
+	// Decode into a copy of the new game, which knows whether it is timed
+	game := *m.game
+	state := savedState{Game: &game}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid chess save: %w", err)
+	}
+	if state.Game == nil {
+		return fmt.Errorf("invalid chess save: no game")
+	}
+
+	m.game = state.Game
+	m.selected = nil
+	m.targets = nil
+	m.cursorX = min(max(state.CursorX, 0), 7)
+	m.cursorY = min(max(state.CursorY, 0), 7)
+	return nil
+}
@@ -0,0 +1,10 @@
+// Package games registers every built-in game with the core registry.
+// Import it for its side effects wherever games are created.
+package games
+
+import (
+	_ "github.com/jakmaz/arcade/internal/games/chess/tui"
+	_ "github.com/jakmaz/arcade/internal/games/snake/tui"
+	_ "github.com/jakmaz/arcade/internal/games/tetris/tui"
+	_ "github.com/jakmaz/arcade/internal/games/tictactoe/tui"
+)
@@ -0,0 +1,189 @@
+package snake
+
+// hamiltonianCycle numbers every cell of an open board so that each cell
+// is adjacent to the next one and the last wraps around to the first. A
+// snake that follows the cycle can never trap itself, which makes it the
+// autopilot's fallback when no safe shortest path exists.
+var hamiltonianCycle = buildHamiltonianCycle()
+
+func buildHamiltonianCycle() [Height][Width]int {
+	var order [Height][Width]int
+	i := 0
+
+	// Along the top row, then down and up the remaining rows column by
+	// column from the right. The board width is even, so the last column
+	// ends next to the starting cell.
+	for x := range Width {
+		order[0][x] = i
+		i++
+	}
+	for col := range Width {
+		x := Width - 1 - col
+		if col%2 == 0 {
+			for y := 1; y < Height; y++ {
+				order[y][x] = i
+				i++
+			}
+		} else {
+			for y := Height - 1; y >= 1; y-- {
+				order[y][x] = i
+				i++
+			}
+		}
+	}
+
+	return order
+}
+
+// SuggestedPath returns the cells the autopilot intends to move through,
+// starting with the cell after the head. It is a shortest path to the
+// nearest food when following it leaves the snake a way back to its
+// tail, and a single safe step otherwise.
+func (g *Game) SuggestedPath() []Position {
+	if len(g.snake) == 0 {
+		return nil
+	}
+
+	isFood := func(p Position) bool {
+		i := g.itemAt(p)
+		return i >= 0 && (g.items[i].Kind == Food || g.items[i].Kind == BonusFood)
+	}
+
+	if path := g.shortestPath(g.snake, isFood); path != nil && g.leavesEscape(path) {
+		return path
+	}
+
+	if next, ok := g.hamiltonianStep(); ok {
+		return []Position{next}
+	}
+
+	if next, ok := g.roomiestStep(); ok {
+		return []Position{next}
+	}
+
+	return nil
+}
+
+// AutopilotDirection picks the next direction for a self-playing snake
+func (g *Game) AutopilotDirection() Direction {
+	path := g.SuggestedPath()
+	if len(path) == 0 {
+		return g.direction
+	}
+	return directionTo(g.snake[0], path[0])
+}
+
+// shortestPath runs a breadth-first search from the head of body to the
+// nearest cell accepted by goal. Body segments other than the tail, which
+// moves away as the snake advances, are treated as obstacles.
+func (g *Game) shortestPath(body []Position, goal func(Position) bool) []Position {
+	blocked := make(map[Position]bool, len(body))
+	for _, segment := range body[:len(body)-1] {
+		blocked[segment] = true
+	}
+
+	start := body[0]
+	previous := map[Position]Position{start: start}
+	queue := []Position{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current != start && goal(current) {
+			var path []Position
+			for p := current; p != start; p = previous[p] {
+				path = append([]Position{p}, path...)
+			}
+			return path
+		}
+
+		for _, d := range directions {
+			next := current.move(d)
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			if !inBounds(next) || g.current.Walls[next.Y][next.X] || blocked[next] {
+				continue
+			}
+			previous[next] = current
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
+
+// leavesEscape simulates following path to the food and reports whether
+// the snake could still reach its own tail afterwards.
+func (g *Game) leavesEscape(path []Position) bool {
+	body := append([]Position(nil), g.snake...)
+	for i, p := range path {
+		body = append([]Position{p}, body...)
+		if i < len(path)-1 {
+			body = body[:len(body)-1]
+		}
+	}
+
+	tail := body[len(body)-1]
+	return g.shortestPath(body, func(p Position) bool { return p == tail }) != nil
+}
+
+// hamiltonianStep returns the next cell on the Hamiltonian cycle. The
+// cycle only exists on boards without walls.
+func (g *Game) hamiltonianStep() (Position, bool) {
+	for y := range Height {
+		for x := range Width {
+			if g.current.Walls[y][x] {
+				return Position{}, false
+			}
+		}
+	}
+
+	head := g.snake[0]
+	want := (hamiltonianCycle[head.Y][head.X] + 1) % (Width * Height)
+	for _, d := range directions {
+		next := head.move(d)
+		if inBounds(next) && hamiltonianCycle[next.Y][next.X] == want && !g.collides(next) {
+			return next, true
+		}
+	}
+	return Position{}, false
+}
+
+// roomiestStep returns the neighbouring cell with the most free space
+// reachable from it.
+func (g *Game) roomiestStep() (Position, bool) {
+	best, bestRoom := Position{}, -1
+	for _, d := range directions {
+		next := g.snake[0].move(d)
+		if d == g.direction.opposite() || g.collides(next) {
+			continue
+		}
+
+		body := append([]Position{next}, g.snake[:len(g.snake)-1]...)
+		room := 0
+		g.shortestPath(body, func(Position) bool {
+			room++
+			return false
+		})
+
+		if room > bestRoom {
+			best, bestRoom = next, room
+		}
+	}
+	return best, bestRoom >= 0
+}
+
+func directionTo(from, to Position) Direction {
+	switch {
+	case to.Y < from.Y:
+		return Up
+	case to.Y > from.Y:
+		return Down
+	case to.X < from.X:
+		return Left
+	default:
+		return Right
+	}
+}
@@ -0,0 +1,169 @@
+package snake
+
+// ItemKind identifies something the snake can pick up
+type ItemKind int
+
+const (
+	Food ItemKind = iota
+	BonusFood
+	SlowMotion
+	Shrink
+	Ghost
+)
+
+const (
+	bonusLifetime   = 40   // ticks a bonus food stays on the board
+	powerUpLifetime = 60   // ticks a power-up stays on the board
+	effectDuration  = 50   // ticks a slow-motion or ghost effect lasts
+	specialChance   = 0.25 // chance of a special item after eating food
+	shrinkAmount    = 3    // segments removed by a shrink power-up
+)
+
+// SpecialKinds are the items that may appear after eating food
+var SpecialKinds = []ItemKind{BonusFood, SlowMotion, Shrink, Ghost}
+
+// Item is a pickup lying on the board
+type Item struct {
+	Kind ItemKind
+	Pos  Position
+	TTL  int // ticks until the item disappears, 0 if it never does
+}
+
+func (k ItemKind) String() string {
+	switch k {
+	case Food:
+		return "Food"
+	case BonusFood:
+		return "Bonus"
+	case SlowMotion:
+		return "Slow"
+	case Shrink:
+		return "Shrink"
+	case Ghost:
+		return "Ghost"
+	}
+	return "Unknown"
+}
+
+func (k ItemKind) points() int {
+	switch k {
+	case Food:
+		return 10
+	case BonusFood:
+		return 50
+	default:
+		return 5
+	}
+}
+
+func (k ItemKind) lifetime() int {
+	switch k {
+	case Food:
+		return 0
+	case BonusFood:
+		return bonusLifetime
+	default:
+		return powerUpLifetime
+	}
+}
+
+// itemAt returns the index of the item at p, or -1
+func (g *Game) itemAt(p Position) int {
+	for i, item := range g.items {
+		if item.Pos == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// spawnItem places an item of the given kind on a random free cell.
+// It reports false when the board has no room left.
+func (g *Game) spawnItem(kind ItemKind) bool {
+	occupied := make(map[Position]bool, len(g.snake)+len(g.items))
+	for _, segment := range g.snake {
+		occupied[segment] = true
+	}
+	for _, item := range g.items {
+		occupied[item.Pos] = true
+	}
+
+	var free []Position
+	for y := range Height {
+		for x := range Width {
+			p := Position{x, y}
+			if !occupied[p] && !g.current.Walls[y][x] {
+				free = append(free, p)
+			}
+		}
+	}
+
+	if len(free) == 0 {
+		return false
+	}
+
+	g.items = append(g.items, Item{
+		Kind: kind,
+		Pos:  free[g.rng.IntN(len(free))],
+		TTL:  kind.lifetime(),
+	})
+	return true
+}
+
+// consume applies the effect of an item the snake just ran into.
+// It reports whether the snake grows by keeping its tail.
+func (g *Game) consume(item Item) bool {
+	g.score += item.Kind.points()
+
+	switch item.Kind {
+	case Food:
+		if !g.spawnItem(Food) {
+			g.won = true
+		}
+		if !g.hasSpecial() && g.rng.Float64() < specialChance {
+			g.spawnItem(SpecialKinds[g.rng.IntN(len(SpecialKinds))])
+		}
+		return true
+	case BonusFood:
+		return true
+	case SlowMotion, Ghost:
+		g.effects[item.Kind] = effectDuration
+	case Shrink:
+		// The head was already added, and the caller drops one more tail segment
+		keep := max(len(g.snake)-1-shrinkAmount, startLength)
+		g.snake = g.snake[:keep+1]
+	}
+	return false
+}
+
+func (g *Game) hasSpecial() bool {
+	for _, item := range g.items {
+		if item.Kind != Food {
+			return true
+		}
+	}
+	return false
+}
+
+// tickItems counts down item lifetimes and active effects
+func (g *Game) tickItems() {
+	kept := g.items[:0]
+	for _, item := range g.items {
+		if item.TTL > 0 {
+			item.TTL--
+			if item.TTL == 0 {
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	g.items = kept
+
+	for kind, remaining := range g.effects {
+		if remaining <= 1 {
+			delete(g.effects, kind)
+		} else {
+			g.effects[kind] = remaining - 1
+		}
+	}
+}
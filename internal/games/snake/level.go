@@ -0,0 +1,238 @@
+package snake
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jakmaz/arcade/internal/paths"
+)
+
+//go:embed levels/*.txt
+var builtinLevels embed.FS
+
+// Level describes a snake map.
+//
+// Levels are stored as plain text: a header of "key: value" lines
+// (name, target, direction), a blank line, and then a grid of exactly
+// Height rows of Width characters:
+//
+//	#  wall
+//	.  empty floor (a space works too)
+//	@  spawn point of the snake's head
+type Level struct {
+	Name      string
+	Target    int // food to eat before the level is cleared, 0 for endless
+	Direction Direction
+	Spawn     Position
+	Walls     [Height][Width]bool
+}
+
+// ParseLevel parses a level from its text representation.
+// The source is only used to make error messages point at the right file.
+func ParseLevel(source string, data []byte) (Level, error) {
+	level := Level{Name: strings.TrimSuffix(filepath.Base(source), filepath.Ext(source)), Direction: Right}
+	spawnFound := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+
+	// Header
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return Level{}, fmt.Errorf("%s:%d: expected \"key: value\", got %q", source, lineNo, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "name":
+			level.Name = value
+		case "target":
+			target, err := strconv.Atoi(value)
+			if err != nil || target < 0 {
+				return Level{}, fmt.Errorf("%s:%d: target must be a non-negative number, got %q", source, lineNo, value)
+			}
+			level.Target = target
+		case "direction":
+			direction, err := parseDirection(value)
+			if err != nil {
+				return Level{}, fmt.Errorf("%s:%d: %w", source, lineNo, err)
+			}
+			level.Direction = direction
+		default:
+			return Level{}, fmt.Errorf("%s:%d: unknown key %q", source, lineNo, key)
+		}
+	}
+
+	// Grid
+	y := 0
+	for scanner.Scan() {
+		lineNo++
+		row := strings.TrimRight(scanner.Text(), "\r")
+		if row == "" && y == Height {
+			continue
+		}
+		if y >= Height {
+			return Level{}, fmt.Errorf("%s:%d: map has more than %d rows", source, lineNo, Height)
+		}
+
+		cells := []rune(row)
+		if len(cells) != Width {
+			return Level{}, fmt.Errorf("%s:%d: row is %d cells wide, expected %d", source, lineNo, len(cells), Width)
+		}
+
+		for x, cell := range cells {
+			switch cell {
+			case '#':
+				level.Walls[y][x] = true
+			case '.', ' ':
+			case '@':
+				if spawnFound {
+					return Level{}, fmt.Errorf("%s:%d: more than one spawn point", source, lineNo)
+				}
+				level.Spawn = Position{x, y}
+				spawnFound = true
+			default:
+				return Level{}, fmt.Errorf("%s:%d: unknown map character %q", source, lineNo, cell)
+			}
+		}
+		y++
+	}
+	if err := scanner.Err(); err != nil {
+		return Level{}, fmt.Errorf("failed to read level %s: %w", source, err)
+	}
+
+	if y != Height {
+		return Level{}, fmt.Errorf("%s: map has %d rows, expected %d", source, y, Height)
+	}
+	if !spawnFound {
+		return Level{}, fmt.Errorf("%s: map has no spawn point", source)
+	}
+
+	for _, segment := range level.startingBody() {
+		if !inBounds(segment) || level.Walls[segment.Y][segment.X] {
+			return Level{}, fmt.Errorf("%s: snake does not fit behind the spawn point", source)
+		}
+	}
+
+	return level, nil
+}
+
+// LoadLevels returns the built-in levels followed by the levels found in
+// the user levels directory. A user level with the same file name as a
+// built-in one replaces it. Files that fail to parse are skipped and
+// reported in the returned errors.
+func LoadLevels() ([]Level, []error) {
+	var errs []error
+	files := make(map[string][]byte)
+	sources := make(map[string]string)
+
+	entries, err := builtinLevels.ReadDir("levels")
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to read built-in levels: %w", err))
+	}
+	for _, entry := range entries {
+		data, err := builtinLevels.ReadFile("levels/" + entry.Name())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files[entry.Name()] = data
+		sources[entry.Name()] = entry.Name()
+	}
+
+	if dir, err := UserLevelsDir(); err == nil {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("failed to read levels directory %s: %w", dir, err))
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".txt" {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to read level %s: %w", path, err))
+				continue
+			}
+			files[entry.Name()] = data
+			sources[entry.Name()] = path
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var levels []Level
+	for _, name := range names {
+		level, err := ParseLevel(sources[name], files[name])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, errs
+}
+
+// UserLevelsDir returns the directory searched for custom levels.
+func UserLevelsDir() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snake", "levels"), nil
+}
+
+// ClassicLevel is the endless, wall-free board of the classic mode.
+func ClassicLevel() Level {
+	return Level{
+		Name:      "Classic",
+		Direction: Right,
+		Spawn:     Position{15, 10},
+	}
+}
+
+// startingBody returns the initial snake, head first, trailing away
+// from the spawn point against the starting direction.
+func (l Level) startingBody() []Position {
+	back := l.Direction.opposite()
+	body := []Position{l.Spawn}
+	for len(body) < startLength {
+		body = append(body, body[len(body)-1].move(back))
+	}
+	return body
+}
+
+func parseDirection(s string) (Direction, error) {
+	switch strings.ToLower(s) {
+	case "up":
+		return Up, nil
+	case "down":
+		return Down, nil
+	case "left":
+		return Left, nil
+	case "right":
+		return Right, nil
+	}
+	return Right, fmt.Errorf("unknown direction %q", s)
+}
@@ -0,0 +1,121 @@
+package snake
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// levelText builds a level file from a header and the cells to change
+// on an empty map
+func levelText(header string, cells map[Position]rune) string {
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString("\n\n")
+	for y := range Height {
+		for x := range Width {
+			if cell, ok := cells[Position{x, y}]; ok {
+				b.WriteRune(cell)
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestParseLevel(t *testing.T) {
+	data := levelText("name: Test\ntarget: 7\ndirection: up", map[Position]rune{
+		{0, 0}:   '#',
+		{29, 19}: '#',
+		{10, 5}:  '@',
+	})
+	level, err := ParseLevel("test.txt", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.Name != "Test" || level.Target != 7 || level.Direction != Up {
+		t.Errorf("header = %q, %d, %v; want Test, 7, up", level.Name, level.Target, level.Direction)
+	}
+	if level.Spawn != (Position{10, 5}) {
+		t.Errorf("Spawn = %v, want {10 5}", level.Spawn)
+	}
+	walls := 0
+	for y := range Height {
+		for x := range Width {
+			if level.Walls[y][x] {
+				walls++
+			}
+		}
+	}
+	if walls != 2 || !level.Walls[0][0] || !level.Walls[19][29] {
+		t.Errorf("got %d walls, want the 2 corners", walls)
+	}
+	// The snake trails down from its head when heading up
+	want := []Position{{10, 5}, {10, 6}, {10, 7}}
+	if got := level.startingBody(); !slices.Equal(got, want) {
+		t.Errorf("startingBody() = %v, want %v", got, want)
+	}
+}
+
+func TestParseLevelDefaults(t *testing.T) {
+	data := levelText("target: 0", map[Position]rune{{5, 5}: '@'})
+	level, err := ParseLevel("levels/open-field.txt", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.Name != "open-field" || level.Direction != Right || level.Target != 0 {
+		t.Errorf("got %q heading %v, want open-field heading right", level.Name, level.Direction)
+	}
+}
+
+func TestParseLevelErrors(t *testing.T) {
+	spawn := map[Position]rune{{10, 10}: '@'}
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"no header separator", "name Test\n", "expected \"key: value\""},
+		{"unknown key", levelText("speed: 3", spawn), "unknown key"},
+		{"negative target", levelText("target: -1", spawn), "target must be a non-negative number"},
+		{"bad target", levelText("target: lots", spawn), "target must be a non-negative number"},
+		{"bad direction", levelText("direction: north", spawn), "unknown direction"},
+		{"no spawn", levelText("name: x", nil), "no spawn point"},
+		{"two spawns", levelText("name: x", map[Position]rune{{10, 10}: '@', {20, 10}: '@'}), "more than one spawn point"},
+		{"unknown cell", levelText("name: x", map[Position]rune{{10, 10}: '@', {3, 3}: 'x'}), "unknown map character"},
+		{"short row", strings.Replace(levelText("name: x", spawn), "\n..............................\n", "\n.............................\n", 1), "row is 29 cells wide"},
+		{"too few rows", strings.TrimSuffix(levelText("name: x", spawn), "..............................\n"), "map has 19 rows"},
+		{"too many rows", levelText("name: x", spawn) + "..............................\n", "more than 20 rows"},
+		{"snake off the board", levelText("name: x", map[Position]rune{{1, 10}: '@'}), "does not fit"},
+		{"snake in a wall", levelText("name: x", map[Position]rune{{10, 10}: '@', {8, 10}: '#'}), "does not fit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLevel("test.txt", []byte(tt.data))
+			if err == nil {
+				t.Fatal("ParseLevel succeeded")
+			}
+			if !strings.Contains(err.Error(), tt.want) || !strings.HasPrefix(err.Error(), "test.txt") {
+				t.Errorf("error %q, want test.txt: ...%s...", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinLevels(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	levels, errs := LoadLevels()
+	for _, err := range errs {
+		t.Error(err)
+	}
+	if len(levels) == 0 {
+		t.Fatal("no built-in levels")
+	}
+	for _, level := range levels {
+		if level.Target <= 0 {
+			t.Errorf("level %s is endless", level.Name)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package snake
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// savedGame is a game as encoded to JSON. Walls are only written, for
+// readers such as bots; they come from the level when a game is restored.
+type savedGame struct {
+	Level         int              `json:"level"`
+	LevelName     string           `json:"level_name"`
+	Walls         []Position       `json:"walls,omitempty"`
+	Snake         []Position       `json:"snake"`
+	Items         []savedItem      `json:"items,omitempty"`
+	Effects       map[ItemKind]int `json:"effects,omitempty"`
+	Direction     Direction        `json:"direction"`
+	NextDirection Direction        `json:"next_direction"`
+	Score         int              `json:"score"`
+	LevelComplete bool             `json:"level_complete,omitempty"`
+}
+
+type savedItem struct {
+	Kind ItemKind `json:"kind"`
+	Pos  Position `json:"pos"`
+	TTL  int      `json:"ttl,omitempty"`
+}
+
+type savedVersus struct {
+	BestOf    int            `json:"best_of"`
+	Players   [2]savedPlayer `json:"players"`
+	Rounds    []savedRound   `json:"rounds,omitempty"`
+	RoundOver bool           `json:"round_over,omitempty"`
+	Crashes   []Position     `json:"crashes,omitempty"`
+}
+
+type savedPlayer struct {
+	Body      []Position `json:"body"`
+	Direction Direction  `json:"direction"`
+	Next      Direction  `json:"next"`
+	Wins      int        `json:"wins"`
+	Crashed   bool       `json:"crashed,omitempty"`
+	Cause     string     `json:"cause,omitempty"`
+}
+
+type savedRound struct {
+	Winner int       `json:"winner"`
+	Causes [2]string `json:"causes"`
+}
+
+// MarshalJSON writes a position as [x, y]
+func (p Position) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]int{p.X, p.Y})
+}
+
+func (p *Position) UnmarshalJSON(data []byte) error {
+	var xy [2]int
+	if err := json.Unmarshal(data, &xy); err != nil {
+		return err
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
+
+func (g *Game) MarshalJSON() ([]byte, error) {
+	state := savedGame{
+		Level:         g.level,
+		LevelName:     g.current.Name,
+		Snake:         g.snake,
+		Effects:       g.effects,
+		Direction:     g.direction,
+		NextDirection: g.nextDirection,
+		Score:         g.score,
+		LevelComplete: g.levelComplete,
+	}
+	for y := range Height {
+		for x := range Width {
+			if g.current.Walls[y][x] {
+				state.Walls = append(state.Walls, Position{x, y})
+			}
+		}
+	}
+	for _, item := range g.items {
+		state.Items = append(state.Items, savedItem{Kind: item.Kind, Pos: item.Pos, TTL: item.TTL})
+	}
+	return json.Marshal(state)
+}
+
+// UnmarshalJSON restores a game into one created by NewGame with the
+// same levels
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var state savedGame
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	// Levels are read from disk, so make sure it is still the same map
+	if state.Level < 0 || state.Level >= len(g.levels) || g.levels[state.Level].Name != state.LevelName {
+		return fmt.Errorf("level %q is no longer available", state.LevelName)
+	}
+
+	if len(state.Snake) == 0 {
+		return fmt.Errorf("the snake has no body")
+	}
+	for _, segment := range state.Snake {
+		if !inBounds(segment) {
+			return fmt.Errorf("the snake is off the board")
+		}
+	}
+
+	var items []Item
+	for _, item := range state.Items {
+		if !inBounds(item.Pos) || item.Kind < Food || item.Kind > Ghost {
+			return fmt.Errorf("unknown item")
+		}
+		items = append(items, Item{Kind: item.Kind, Pos: item.Pos, TTL: item.TTL})
+	}
+
+	g.level = state.Level
+	g.current = g.levels[state.Level]
+	g.over = false
+	g.won = false
+	g.snake = state.Snake
+	g.items = items
+	g.effects = state.Effects
+	if g.effects == nil {
+		g.effects = make(map[ItemKind]int)
+	}
+	g.direction = state.Direction
+	g.nextDirection = state.NextDirection
+	g.score = state.Score
+	g.levelComplete = state.LevelComplete
+	return nil
+}
+
+func (v *Versus) MarshalJSON() ([]byte, error) {
+	state := savedVersus{
+		BestOf:    v.bestOf,
+		RoundOver: v.roundOver,
+		Crashes:   v.crashes,
+	}
+	for i, p := range v.players {
+		state.Players[i] = savedPlayer{
+			Body:      p.Body,
+			Direction: p.direction,
+			Next:      p.next,
+			Wins:      p.Wins,
+			Crashed:   p.Crashed,
+			Cause:     p.Cause,
+		}
+	}
+	for _, round := range v.rounds {
+		state.Rounds = append(state.Rounds, savedRound{Winner: round.Winner, Causes: round.Causes})
+	}
+	return json.Marshal(state)
+}
+
+func (v *Versus) UnmarshalJSON(data []byte) error {
+	var state savedVersus
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.BestOf < 1 {
+		return fmt.Errorf("invalid match length %d", state.BestOf)
+	}
+
+	restored := NewVersus(state.BestOf)
+	restored.roundOver = state.RoundOver
+	restored.crashes = state.Crashes
+	for i, p := range state.Players {
+		if len(p.Body) == 0 {
+			return fmt.Errorf("player %d has no body", i+1)
+		}
+		for _, segment := range p.Body {
+			if !inBounds(segment) {
+				return fmt.Errorf("player %d is off the board", i+1)
+			}
+		}
+		player := restored.players[i]
+		player.Body = p.Body
+		player.direction = p.Direction
+		player.next = p.Next
+		player.Wins = p.Wins
+		player.Crashed = p.Crashed
+		player.Cause = p.Cause
+	}
+	for _, round := range state.Rounds {
+		restored.rounds = append(restored.rounds, Round{Winner: round.Winner, Causes: round.Causes})
+	}
+
+	*v = *restored
+	return nil
+}
@@ -1,26 +1,49 @@
+// Package snake is the snake engine: a single snake working through
+// one or more levels, and a two-player versus match. Both advance one
+// cell per Tick and turn with actions named after directions, e.g. "up".
 package snake
 
 import (
 	"fmt"
-	"strings"
+	"math/rand/v2"
+	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/jakmaz/arcade/internal/ui/styles"
+	"github.com/jakmaz/arcade/internal/engine"
 )
 
-type Model struct {
-	board         [20][30]rune
+const (
+	Width       = 30
+	Height      = 20
+	startLength = 3
+	tickRate    = 120 * time.Millisecond
+)
+
+// Next starts the next level once the current one is cleared, or the
+// next round of a versus match
+const Next engine.Action = "next"
+
+// Game is a snake game played through a list of levels. The classic
+// game is the single endless ClassicLevel.
+type Game struct {
+	rng           *rand.Rand
+	levels        []Level
+	level         int
+	current       Level
 	snake         []Position
-	food          Position
+	items         []Item
+	effects       map[ItemKind]int
 	direction     Direction
+	nextDirection Direction
 	score         int
-	gameOver      bool
-	width, height int
+	over          bool
+	levelComplete bool
+	won           bool
 }
 
+var _ engine.Realtime = (*Game)(nil)
+
 type Position struct {
-	x, y int
+	X, Y int
 }
 
 type Direction int
@@ -32,121 +55,204 @@ const (
 	Right
 )
 
-func New() *Model {
-	m := &Model{
-		snake: []Position{
-			{15, 10},
-			{14, 10},
-			{13, 10},
-		},
-		food:      Position{20, 10},
-		direction: Right,
-		score:     0,
-		gameOver:  false,
+var directions = []Direction{Up, Down, Left, Right}
+
+func (p Position) move(d Direction) Position {
+	switch d {
+	case Up:
+		p.Y--
+	case Down:
+		p.Y++
+	case Left:
+		p.X--
+	case Right:
+		p.X++
 	}
-	m.updateBoard()
-	return m
+	return p
 }
 
-func (m *Model) Init() tea.Cmd {
-	return nil
+func (d Direction) String() string {
+	switch d {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case Left:
+		return "left"
+	default:
+		return "right"
+	}
 }
 
-func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+func (d Direction) opposite() Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	default:
+		return Left
 	}
-	return m, nil
 }
 
-func (m *Model) View() string {
-	title := styles.TitleStyle.Render("Snake")
+func inBounds(p Position) bool {
+	return p.X >= 0 && p.X < Width && p.Y >= 0 && p.Y < Height
+}
 
-	board := m.renderBoard()
+// Turn returns the action steering the snake in direction d
+func Turn(d Direction) engine.Action {
+	return engine.Action(d.String())
+}
 
-	var status string
-	if m.gameOver {
-		status = styles.GameOverStyle.Render(fmt.Sprintf("Game Over! Final Score: %d", m.score))
-	} else {
-		status = styles.SelectedItemStyle.Render(fmt.Sprintf("Score: %d", m.score))
-	}
+// NewGame starts on the first of levels, placing items with rng
+func NewGame(rng *rand.Rand, levels []Level) *Game {
+	g := &Game{rng: rng, levels: levels}
+	g.loadLevel(0)
+	return g
+}
 
-	help := styles.HelpStyle.Render("↑ ↓ ← → to move, Space to pause, ESC to return to menu")
+func (g *Game) loadLevel(i int) {
+	g.level = i
+	g.current = g.levels[i]
+	g.snake = g.current.startingBody()
+	g.direction = g.current.Direction
+	g.nextDirection = g.current.Direction
+	g.items = nil
+	g.effects = make(map[ItemKind]int)
+	g.over = false
+	g.levelComplete = false
+	g.won = false
+	g.spawnItem(Food)
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		title,
-		"",
-		board,
-		"",
-		status,
-		"",
-		help,
-	)
+// Step turns the snake on its next move, or starts the next level once
+// the current one is cleared. Turning back onto itself is illegal.
+func (g *Game) Step(action engine.Action) error {
+	if g.levelComplete && action == Next {
+		g.loadLevel(g.level + 1)
+		return nil
+	}
 
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+	d, err := parseDirection(string(action))
+	if err != nil || g.over || g.won || g.levelComplete || d == g.direction.opposite() {
+		return fmt.Errorf("%w: %s", engine.ErrIllegal, action)
+	}
+	g.nextDirection = d
+	return nil
 }
 
-func (m *Model) updateBoard() {
-	for y := range 20 {
-		for x := range 30 {
-			m.board[y][x] = ' '
-		}
+func (g *Game) Legal() []engine.Action {
+	switch {
+	case g.over || g.won:
+		return nil
+	case g.levelComplete:
+		return []engine.Action{Next}
 	}
 
-	for _, segment := range m.snake {
-		if segment.y >= 0 && segment.y < 20 && segment.x >= 0 && segment.x < 30 {
-			m.board[segment.y][segment.x] = '●'
+	var actions []engine.Action
+	for _, d := range directions {
+		if d != g.direction.opposite() {
+			actions = append(actions, Turn(d))
 		}
 	}
+	return actions
+}
 
-	if len(m.snake) > 0 {
-		head := m.snake[0]
-		if head.y >= 0 && head.y < 20 && head.x >= 0 && head.x < 30 {
-			m.board[head.y][head.x] = '◉'
-		}
+func (g *Game) Result() engine.Result {
+	return engine.Result{Over: g.over || g.won, Score: g.score}
+}
+
+// Tick advances the snake by one cell
+func (g *Game) Tick() {
+	if g.over || g.levelComplete || g.won {
+		return
 	}
 
-	if m.food.y >= 0 && m.food.y < 20 && m.food.x >= 0 && m.food.x < 30 {
-		m.board[m.food.y][m.food.x] = '🍎'
+	g.direction = g.nextDirection
+	head := g.snake[0].move(g.direction)
+
+	if g.collides(head) {
+		g.over = true
+		return
 	}
-}
 
-func (m *Model) renderBoard() string {
-	foodStyle := lipgloss.NewStyle().
-		Bold(true)
+	g.snake = append([]Position{head}, g.snake...)
+	grows := false
+	if i := g.itemAt(head); i >= 0 {
+		item := g.items[i]
+		g.items = append(g.items[:i], g.items[i+1:]...)
+		grows = g.consume(item)
+	}
+	if !grows {
+		g.snake = g.snake[:len(g.snake)-1]
+	}
 
-	var rows []string
+	if g.current.Target > 0 && len(g.snake) >= g.TargetLength() {
+		if g.level == len(g.levels)-1 {
+			g.won = true
+		} else {
+			g.levelComplete = true
+		}
+	}
 
-	topBorder := styles.BorderStyle.Render("┌" + strings.Repeat("─", 30) + "┐")
-	rows = append(rows, topBorder)
+	g.tickItems()
+}
 
-	for y := range 20 {
-		var rowContent strings.Builder
-		rowContent.WriteString(styles.BorderStyle.Render("│"))
+// Interval returns the time between moves, doubled under slow motion
+func (g *Game) Interval() time.Duration {
+	if _, slow := g.effects[SlowMotion]; slow {
+		return tickRate * 2
+	}
+	return tickRate
+}
 
-		for x := range 30 {
-			cell := m.board[y][x]
-			switch cell {
-			case '◉':
-				rowContent.WriteString(styles.SnakeHeadStyle.Render("◉"))
-			case '●':
-				rowContent.WriteString(styles.SnakeStyle.Render("●"))
-			case '🍎':
-				rowContent.WriteString(foodStyle.Render("🍎"))
-			default:
-				rowContent.WriteString(" ")
-			}
+func (g *Game) collides(p Position) bool {
+	if !inBounds(p) || g.current.Walls[p.Y][p.X] {
+		return true
+	}
+	if _, ghost := g.effects[Ghost]; ghost {
+		return false
+	}
+	// The tail moves out of the way on this tick, so it is safe to enter
+	for _, segment := range g.snake[:len(g.snake)-1] {
+		if segment == p {
+			return true
 		}
-		rowContent.WriteString(styles.BorderStyle.Render("│"))
-		rows = append(rows, rowContent.String())
 	}
+	return false
+}
 
-	bottomBorder := styles.BorderStyle.Render("└" + strings.Repeat("─", 30) + "┘")
-	rows = append(rows, bottomBorder)
-
-	return strings.Join(rows, "\n")
+// TargetLength is the length that clears the current level
+func (g *Game) TargetLength() int {
+	return startLength + g.current.Target
 }
 
-type tickMsg struct{}
+// Snake returns the body, head first
+func (g *Game) Snake() []Position { return g.snake }
+
+// Items returns the pickups lying on the board
+func (g *Game) Items() []Item { return g.items }
+
+// Effects returns the ticks left of every active power-up
+func (g *Game) Effects() map[ItemKind]int { return g.effects }
+
+// Level returns the index of the level being played
+func (g *Game) Level() int { return g.level }
+
+// CurrentLevel returns the map being played
+func (g *Game) CurrentLevel() Level { return g.current }
+
+// Levels returns the number of levels in the game
+func (g *Game) Levels() int { return len(g.levels) }
+
+// LevelComplete reports whether the snake cleared a level and waits for
+// Next
+func (g *Game) LevelComplete() bool { return g.levelComplete }
+
+// Won reports whether the last level was cleared, or the board filled up
+func (g *Game) Won() bool { return g.won }
+
+// Crashed reports whether the snake hit a wall or itself
+func (g *Game) Crashed() bool { return g.over }
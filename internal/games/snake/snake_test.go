@@ -0,0 +1,189 @@
+package snake
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+// newTestGame starts a game on level with no items on the board, and
+// the snake laid out as body heading in direction d
+func newTestGame(level Level, body []Position, d Direction) *Game {
+	g := NewGame(rand.New(rand.NewPCG(1, 2)), []Level{level})
+	g.items = nil
+	g.snake = body
+	g.direction, g.nextDirection = d, d
+	return g
+}
+
+func TestCollisions(t *testing.T) {
+	walled := ClassicLevel()
+	walled.Walls[10][12] = true
+
+	// A snake of five curled up so that its head can reach its own body
+	curled := []Position{{10, 10}, {11, 10}, {11, 11}, {10, 11}, {9, 11}}
+
+	tests := []struct {
+		name  string
+		level Level
+		body  []Position
+		turn  Direction
+		crash bool
+	}{
+		{"open floor", ClassicLevel(), []Position{{10, 10}, {9, 10}, {8, 10}}, Right, false},
+		{"wall", walled, []Position{{11, 10}, {10, 10}, {9, 10}}, Right, true},
+		{"right edge", ClassicLevel(), []Position{{Width - 1, 5}, {Width - 2, 5}, {Width - 3, 5}}, Right, true},
+		{"left edge", ClassicLevel(), []Position{{0, 5}, {1, 5}, {2, 5}}, Left, true},
+		{"top edge", ClassicLevel(), []Position{{5, 0}, {5, 1}, {5, 2}}, Up, true},
+		{"bottom edge", ClassicLevel(), []Position{{5, Height - 1}, {5, Height - 2}, {5, Height - 3}}, Down, true},
+		{"own body", ClassicLevel(), curled, Down, true},
+		// The tail leaves the cell on the same tick
+		{"own tail", ClassicLevel(), []Position{{10, 10}, {11, 10}, {11, 11}, {10, 11}}, Down, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(tt.level, tt.body, tt.body[0].directionFrom(tt.body[1]))
+			if err := g.Step(Turn(tt.turn)); err != nil {
+				t.Fatal(err)
+			}
+			g.Tick()
+			if g.Crashed() != tt.crash {
+				t.Errorf("Crashed() = %v, want %v", g.Crashed(), tt.crash)
+			}
+			if tt.crash && !g.Result().Over {
+				t.Error("crashed game is not over")
+			}
+		})
+	}
+}
+
+func TestGhostPassesThroughBody(t *testing.T) {
+	g := newTestGame(ClassicLevel(), []Position{{10, 10}, {11, 10}, {11, 11}, {10, 11}, {9, 11}}, Left)
+	g.effects[Ghost] = effectDuration
+	g.Step(Turn(Down))
+	g.Tick()
+	if g.Crashed() {
+		t.Error("ghost snake crashed into itself")
+	}
+
+	// Walls still stop it
+	g = newTestGame(ClassicLevel(), []Position{{0, 5}, {1, 5}, {2, 5}}, Left)
+	g.effects[Ghost] = effectDuration
+	g.Tick()
+	if !g.Crashed() {
+		t.Error("ghost snake left the board")
+	}
+}
+
+func TestTurningBackIsIllegal(t *testing.T) {
+	g := newTestGame(ClassicLevel(), []Position{{10, 10}, {9, 10}, {8, 10}}, Right)
+	if err := g.Step(Turn(Left)); !errors.Is(err, engine.ErrIllegal) {
+		t.Errorf("Step(left) = %v, want ErrIllegal", err)
+	}
+	for _, action := range g.Legal() {
+		if action == Turn(Left) {
+			t.Error("left is among the legal moves")
+		}
+	}
+	if err := g.Step("sideways"); !errors.Is(err, engine.ErrIllegal) {
+		t.Errorf("Step(sideways) = %v, want ErrIllegal", err)
+	}
+}
+
+func TestEatingGrows(t *testing.T) {
+	g := newTestGame(ClassicLevel(), []Position{{10, 10}, {9, 10}, {8, 10}}, Right)
+	g.items = []Item{{Kind: Food, Pos: Position{11, 10}}}
+	g.Tick()
+	if got := len(g.Snake()); got != 4 {
+		t.Errorf("snake is %d long after eating, want 4", got)
+	}
+	if got := g.Result().Score; got != 10 {
+		t.Errorf("score = %d, want 10", got)
+	}
+	g.Tick()
+	if got := len(g.Snake()); got != 4 {
+		t.Errorf("snake is %d long after moving on, want 4", got)
+	}
+}
+
+func TestLevelComplete(t *testing.T) {
+	level := ClassicLevel()
+	level.Target = 1
+	next := ClassicLevel()
+	next.Target = 1
+	g := NewGame(rand.New(rand.NewPCG(1, 2)), []Level{level, next})
+	g.items = []Item{{Kind: Food, Pos: g.Snake()[0].move(Right)}}
+
+	g.Tick()
+	if !g.LevelComplete() {
+		t.Fatal("level not complete at the target length")
+	}
+	if got := g.Legal(); len(got) != 1 || got[0] != Next {
+		t.Errorf("Legal() = %v, want [next]", got)
+	}
+	if err := g.Step(Next); err != nil {
+		t.Fatal(err)
+	}
+	if g.Level() != 1 || len(g.Snake()) != startLength {
+		t.Errorf("on level %d with a snake of %d, want level 1 and a new snake", g.Level(), len(g.Snake()))
+	}
+
+	g.items = []Item{{Kind: Food, Pos: g.Snake()[0].move(Right)}}
+	g.Tick()
+	if !g.Won() || !g.Result().Over {
+		t.Error("clearing the last level doesn't win")
+	}
+}
+
+func TestVersusCollisions(t *testing.T) {
+	tests := []struct {
+		name   string
+		bodies [2][]Position
+		turns  [2]Direction
+		winner int
+		causes [2]string
+	}{
+		{"head-on", [2][]Position{{{10, 5}, {9, 5}}, {{12, 5}, {13, 5}}}, [2]Direction{Right, Left},
+			-1, [2]string{"head-on collision", "head-on collision"}},
+		{"passing through", [2][]Position{{{10, 5}, {9, 5}}, {{11, 5}, {12, 5}}}, [2]Direction{Right, Left},
+			-1, [2]string{"head-on collision", "head-on collision"}},
+		{"into the other", [2][]Position{{{10, 5}, {9, 5}}, {{11, 4}, {11, 5}, {11, 6}}}, [2]Direction{Right, Up},
+			1, [2]string{"ran into Player 2", ""}},
+		{"into the wall", [2][]Position{{{10, 0}, {10, 1}}, {{20, 10}, {21, 10}}}, [2]Direction{Up, Left},
+			1, [2]string{"hit the wall", ""}},
+		{"into itself", [2][]Position{{{10, 10}, {11, 10}}, {{5, 5}, {5, 6}, {6, 6}, {6, 5}, {6, 4}}}, [2]Direction{Up, Right},
+			0, [2]string{"", "ran into itself"}},
+		{"both crash", [2][]Position{{{10, 0}, {10, 1}}, {{0, 10}, {1, 10}}}, [2]Direction{Up, Left},
+			-1, [2]string{"hit the wall", "hit the wall"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVersus(3)
+			for i, p := range v.players {
+				p.Body = tt.bodies[i]
+				p.direction = p.Body[0].directionFrom(p.Body[1])
+				p.next = tt.turns[i]
+			}
+			v.Tick()
+			if !v.RoundOver() {
+				t.Fatal("round not over")
+			}
+			round := v.Rounds()[0]
+			if round.Winner != tt.winner || round.Causes != tt.causes {
+				t.Errorf("round = %+v, want winner %d, causes %q", round, tt.winner, tt.causes)
+			}
+		})
+	}
+}
+
+// directionFrom returns the direction moving from the cell before p to p
+func (p Position) directionFrom(before Position) Direction {
+	for _, d := range directions {
+		if before.move(d) == p {
+			return d
+		}
+	}
+	return Right
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/games/snake"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+func itemGlyph(k snake.ItemKind) rune {
+	switch k {
+	case snake.BonusFood:
+		return '★'
+	case snake.SlowMotion:
+		return '◷'
+	case snake.Shrink:
+		return '▼'
+	case snake.Ghost:
+		return '◌'
+	default:
+		return '◆'
+	}
+}
+
+func itemStyle(k snake.ItemKind) lipgloss.Style {
+	switch k {
+	case snake.BonusFood:
+		return styles.WarningStyle
+	case snake.SlowMotion:
+		return styles.SelectedItemStyle
+	case snake.Shrink:
+		return styles.ErrorStyle
+	case snake.Ghost:
+		return styles.MenuItemStyle
+	default:
+		return styles.FoodStyle
+	}
+}
+
+// renderTimers describes active effects and expiring items for the status bar
+func (m *Model) renderTimers() string {
+	var parts []string
+	effects := m.game.Effects()
+	for _, kind := range snake.SpecialKinds {
+		if remaining, ok := effects[kind]; ok {
+			parts = append(parts, itemStyle(kind).Render(fmt.Sprintf("%c %s %d", itemGlyph(kind), kind, remaining)))
+		}
+	}
+	for _, item := range m.game.Items() {
+		if item.TTL > 0 {
+			parts = append(parts, itemStyle(item.Kind).Render(fmt.Sprintf("%c %s fades in %d", itemGlyph(item.Kind), item.Kind, item.TTL)))
+		}
+	}
+
+	return strings.Join(parts, "   ")
+}
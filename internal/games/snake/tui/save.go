@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/jakmaz/arcade/internal/games/snake"
+)
+
+// savedState is a game in progress as written to a save file
+type savedState struct {
+	Mode     string        `json:"mode"`
+	Assisted bool          `json:"assisted,omitempty"`
+	Game     *snake.Game   `json:"game,omitempty"`
+	Versus   *snake.Versus `json:"versus,omitempty"`
+}
+
+func (m *Model) SaveState() ([]byte, error) {
+	if !m.started {
+		return nil, fmt.Errorf("no mode chosen yet")
+	}
+	return json.Marshal(savedState{
+		Mode:     modeKeys[m.mode],
+		Assisted: m.assisted,
+		Game:     m.game,
+		Versus:   m.versus,
+	})
+}
+
+func (m *Model) LoadState(data []byte) error {
+	var peek struct {
+		Mode string `json:"mode"`
+	}
+	if err := json.Unmarshal(data, &peek); err != nil {
+		return fmt.Errorf("invalid snake save: %w", err)
+	}
+	mode := slices.Index(modeKeys, peek.Mode)
+	if mode < 0 {
+		return fmt.Errorf("invalid snake save: unknown mode %q", peek.Mode)
+	}
+
+	// Decode into a new game of the saved mode, which brings the levels
+	m.mode = Mode(mode)
+	m.modeCursor = mode
+	m.started = true
+	m.restart()
+
+	state := savedState{Game: m.game, Versus: m.versus}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid snake save: %w", err)
+	}
+	if (m.versus != nil && state.Versus == nil) || (m.game != nil && state.Game == nil) {
+		return fmt.Errorf("invalid snake save: no game")
+	}
+	if m.versus != nil {
+		if i := slices.Index(bestOfChoices, m.versus.BestOf()); i >= 0 {
+			m.bestOfIndex = i
+		}
+	}
+	m.assisted = state.Assisted
+	return nil
+}
@@ -0,0 +1,503 @@
+// Package tui plays the snake engine in the terminal, adding the mode
+// menu, the autopilot and the attract-screen demo
+package tui
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/games/snake"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+func init() {
+	core.Register(core.GameInfo{
+		ID:          "snake",
+		Name:        "Snake",
+		Description: "Classic Snake game",
+		Options: []core.OptionInfo{
+			{Key: "mode", Values: modeKeys, Help: "skip the mode menu and start this mode"},
+			{Key: "bestof", Values: []string{"1", "3", "5", "7"}, Default: "5", Help: "rounds in a versus match"},
+		},
+		New: func(options core.Options) core.Game { return New(options) },
+	})
+}
+
+// Mode selects between the endless classic game, the level progression
+// and the two-player match
+type Mode int
+
+const (
+	ClassicMode Mode = iota
+	LevelMode
+	VersusMode
+)
+
+var (
+	modeNames = []string{"Classic", "Levels", "Versus"}
+	modeKeys  = []string{"classic", "levels", "versus"}
+)
+
+// bestOfChoices are the match lengths selectable for versus mode
+var bestOfChoices = []int{1, 3, 5, 7}
+
+type Model struct {
+	rng           *rand.Rand
+	game          *snake.Game   // set in classic and level mode
+	versus        *snake.Versus // set in versus mode
+	paused        bool
+	width, height int
+
+	// Mode selection
+	started     bool
+	mode        Mode
+	modeCursor  int
+	levels      []snake.Level
+	levelErrs   []error
+	bestOfIndex int
+
+	// Autopilot and the hint overlay showing its planned path
+	autopilot bool
+	assisted  bool // the autopilot played part of this game
+	hint      bool
+	demo      bool
+}
+
+// New creates a snake game. Without a "mode" option the player picks
+// the mode from a menu first.
+func New(options core.Options) *Model {
+	m := &Model{rng: options.Rand()}
+	m.levels, m.levelErrs = snake.LoadLevels()
+
+	m.bestOfIndex = slices.Index(bestOfChoices, atoi(options.Get("bestof", "5")))
+	if m.bestOfIndex < 0 {
+		m.bestOfIndex = 2
+	}
+
+	if mode := slices.Index(modeKeys, options.Get("mode", "")); mode >= 0 {
+		m.modeCursor = mode
+		m.startMode(Mode(mode))
+	}
+	return m
+}
+
+// NewDemo returns a classic game that plays itself on autopilot and
+// restarts whenever it dies, for use as an attract screen.
+func NewDemo() *Model {
+	m := New(nil)
+	m.demo = true
+	m.autopilot = true
+	m.startMode(ClassicMode)
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case core.TickMsg:
+		m.tick()
+
+	case tea.KeyMsg:
+		if !m.started {
+			m.updateModeSelect(msg)
+			return m, nil
+		}
+
+		if m.versus != nil {
+			if action, ok := versusKeys[msg.String()]; ok {
+				m.versus.Step(action)
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "up", "w":
+			m.game.Step(snake.Turn(snake.Up))
+		case "down", "s":
+			m.game.Step(snake.Turn(snake.Down))
+		case "left", "a":
+			m.game.Step(snake.Turn(snake.Left))
+		case "right", "d":
+			m.game.Step(snake.Turn(snake.Right))
+		case "enter":
+			m.game.Step(snake.Next)
+		case "h":
+			m.hint = !m.hint
+		case "tab":
+			m.autopilot = !m.autopilot
+			m.assisted = true
+		}
+	}
+	return m, nil
+}
+
+// versusKeys steer player 1 with WASD and player 2 with the arrows
+var versusKeys = map[string]engine.Action{
+	"w":     snake.TurnPlayer(0, snake.Up),
+	"s":     snake.TurnPlayer(0, snake.Down),
+	"a":     snake.TurnPlayer(0, snake.Left),
+	"d":     snake.TurnPlayer(0, snake.Right),
+	"up":    snake.TurnPlayer(1, snake.Up),
+	"down":  snake.TurnPlayer(1, snake.Down),
+	"left":  snake.TurnPlayer(1, snake.Left),
+	"right": snake.TurnPlayer(1, snake.Right),
+	"enter": snake.Next,
+}
+
+func (m *Model) updateModeSelect(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "up", "w":
+		m.modeCursor = (m.modeCursor - 1 + len(modeNames)) % len(modeNames)
+	case "down", "s":
+		m.modeCursor = (m.modeCursor + 1) % len(modeNames)
+	case "left", "a":
+		if Mode(m.modeCursor) == VersusMode {
+			m.bestOfIndex = (m.bestOfIndex - 1 + len(bestOfChoices)) % len(bestOfChoices)
+		}
+	case "right", "d":
+		if Mode(m.modeCursor) == VersusMode {
+			m.bestOfIndex = (m.bestOfIndex + 1) % len(bestOfChoices)
+		}
+	case "enter":
+		m.startMode(Mode(m.modeCursor))
+	}
+}
+
+// startMode leaves the mode menu and starts playing
+func (m *Model) startMode(mode Mode) {
+	if mode == LevelMode && len(m.levels) == 0 {
+		return
+	}
+	m.mode = mode
+	m.started = true
+	m.restart()
+}
+
+// restart begins the selected mode from the start
+func (m *Model) restart() {
+	m.assisted = m.autopilot
+	m.paused = false
+	m.game = nil
+	m.versus = nil
+	switch m.mode {
+	case VersusMode:
+		m.versus = snake.NewVersus(bestOfChoices[m.bestOfIndex])
+	case LevelMode:
+		m.game = snake.NewGame(m.rng, m.levels)
+	default:
+		m.game = snake.NewGame(m.rng, []snake.Level{snake.ClassicLevel()})
+	}
+}
+
+// tick advances the game, steering first when the autopilot is on
+func (m *Model) tick() {
+	if !m.started || m.paused {
+		return
+	}
+	if m.versus != nil {
+		m.versus.Tick()
+		return
+	}
+
+	if m.demo && m.game.Result().Over {
+		m.restart()
+		return
+	}
+
+	if m.autopilot {
+		m.game.Step(snake.Turn(m.game.AutopilotDirection()))
+	}
+
+	length, cleared := len(m.game.Snake()), m.cleared()
+	m.game.Tick()
+	if len(m.game.Snake()) > length {
+		m.publish("length", len(m.game.Snake()))
+	}
+	if !cleared && m.cleared() {
+		m.publish("level_complete", m.game.Level()+1)
+	}
+}
+
+// cleared reports whether the snake reached the target of its level
+func (m *Model) cleared() bool {
+	return m.game.LevelComplete() || (m.game.Won() && m.game.CurrentLevel().Target > 0)
+}
+
+// publish reports an event of a game played by hand. Games the
+// autopilot helped with don't count.
+func (m *Model) publish(name string, value int) {
+	if m.assisted || m.demo {
+		return
+	}
+	core.Publish(core.Event{Game: "snake", Name: name, Value: value})
+}
+
+// Pause freezes the snake. It has no effect on the mode menu.
+func (m *Model) Pause() {
+	if m.started {
+		m.paused = true
+	}
+}
+
+func (m *Model) Resume() { m.paused = false }
+
+func (m *Model) Engine() engine.Engine {
+	switch {
+	case m.versus != nil:
+		return m.versus
+	case m.game != nil:
+		return m.game
+	}
+	return nil
+}
+
+func (m *Model) State() core.State {
+	state := core.State{Paused: m.paused}
+	if !m.started {
+		return state
+	}
+	state.Mode = modeKeys[m.mode]
+
+	if m.versus != nil {
+		result := m.versus.Result()
+		state.Score = result.Score
+		state.Over = result.Over
+		state.Outcome = result.Outcome
+		state.Winner = result.Winner
+		return state
+	}
+
+	result := m.game.Result()
+	state.Score = result.Score
+	state.Over = result.Over
+	state.Assisted = m.assisted || m.demo
+	state.Details = map[string]int{"length": len(m.game.Snake())}
+	if m.mode == LevelMode {
+		state.Details["level"] = m.game.Level() + 1
+	}
+	return state
+}
+
+func (m *Model) KeyBindings() []core.KeyBinding {
+	switch {
+	case !m.started:
+		return []core.KeyBinding{
+			{Keys: "↑ ↓", Help: "choose a mode"},
+			{Keys: "← →", Help: "change versus rounds"},
+			{Keys: "Enter", Help: "start"},
+		}
+	case m.versus != nil:
+		return []core.KeyBinding{
+			{Keys: "W A S D", Help: "steer player 1"},
+			{Keys: "↑ ↓ ← →", Help: "steer player 2"},
+			{Keys: "Enter", Help: "start the next round"},
+		}
+	}
+	return []core.KeyBinding{
+		{Keys: "↑ ↓ ← →", Help: "move"},
+		{Keys: "H", Help: "show a hint"},
+		{Keys: "Tab", Help: "toggle autopilot"},
+	}
+}
+
+// TickInterval returns the time between moves of the game being played
+func (m *Model) TickInterval() time.Duration {
+	switch {
+	case m.versus != nil:
+		return m.versus.Interval()
+	case m.game != nil:
+		return m.game.Interval()
+	}
+	return 0
+}
+
+func (m *Model) View() string {
+	title := styles.TitleStyle.Render("Snake")
+
+	if !m.started {
+		return m.viewModeSelect(title)
+	}
+
+	if m.versus != nil {
+		return m.viewVersus(title)
+	}
+
+	board := m.renderBoard()
+
+	g := m.game
+	score := g.Result().Score
+	var status string
+	switch {
+	case m.demo:
+		status = styles.SelectedItemStyle.Render(fmt.Sprintf("Autopilot demo - Score: %d", score))
+	case g.Crashed():
+		status = styles.GameOverStyle.Render(fmt.Sprintf("Game Over! Final Score: %d", score))
+	case g.Won():
+		status = styles.SelectedItemStyle.Render(fmt.Sprintf("You win! Final Score: %d", score))
+	case g.LevelComplete():
+		status = styles.SelectedItemStyle.Render(fmt.Sprintf("%s cleared! Score: %d", g.CurrentLevel().Name, score))
+	case m.mode == LevelMode:
+		status = styles.SelectedItemStyle.Render(fmt.Sprintf("Level %d/%d: %s   Length: %d/%d   Score: %d",
+			g.Level()+1, g.Levels(), g.CurrentLevel().Name, len(g.Snake()), g.TargetLength(), score))
+	default:
+		status = styles.SelectedItemStyle.Render(fmt.Sprintf("Score: %d", score))
+	}
+
+	if m.autopilot && !m.demo {
+		status += styles.MenuItemStyle.Render("   Autopilot")
+	}
+
+	var help string
+	if g.LevelComplete() {
+		help = styles.HelpStyle.Render(core.HelpLine([]core.KeyBinding{{Keys: "Enter", Help: "play the next level"}}))
+	} else {
+		help = styles.HelpStyle.Render(core.HelpLine(m.KeyBindings()))
+	}
+
+	if m.demo {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Center, board, "", status))
+	}
+
+	timers := m.renderTimers()
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		board,
+		"",
+		status,
+		timers,
+		help,
+	)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m *Model) viewModeSelect(title string) string {
+	var items []string
+	for i, name := range modeNames {
+		label := name
+		switch Mode(i) {
+		case LevelMode:
+			label = fmt.Sprintf("%s (%d maps)", name, len(m.levels))
+		case VersusMode:
+			label = fmt.Sprintf("%s (best of ← %d →)", name, bestOfChoices[m.bestOfIndex])
+		}
+		if i == m.modeCursor {
+			items = append(items, styles.SelectedItemStyle.Render("> "+label))
+		} else {
+			items = append(items, styles.MenuItemStyle.Render("  "+label))
+		}
+	}
+
+	sections := []string{title, "", strings.Join(items, "\n")}
+
+	if len(m.levelErrs) > 0 {
+		warning := fmt.Sprintf("Skipped %d level file(s): %v", len(m.levelErrs), m.levelErrs[0])
+		sections = append(sections, "", styles.GameOverStyle.Render(warning))
+	}
+
+	sections = append(sections, "", styles.HelpStyle.Render(core.HelpLine(m.KeyBindings())))
+
+	content := lipgloss.JoinVertical(lipgloss.Center, sections...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m *Model) viewVersus(title string) string {
+	var board string
+	if m.versus.RoundOver() {
+		board = renderVersusSummary(m.versus)
+	} else {
+		board = renderVersus(m.versus)
+	}
+
+	status := renderVersusScore(m.versus)
+
+	help := styles.HelpStyle.Render(core.HelpLine(m.KeyBindings()))
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		board,
+		"",
+		status,
+		"",
+		help,
+	)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m *Model) renderBoard() string {
+	g := m.game
+
+	var grid [snake.Height][snake.Width]string
+	walls := g.CurrentLevel().Walls
+	for y := range snake.Height {
+		for x := range snake.Width {
+			if walls[y][x] {
+				grid[y][x] = styles.BorderStyle.Render("▓")
+			} else {
+				grid[y][x] = " "
+			}
+		}
+	}
+
+	if m.hint && !g.Crashed() {
+		for _, p := range g.SuggestedPath() {
+			grid[p.Y][p.X] = styles.SelectedItemStyle.Render("·")
+		}
+	}
+
+	if !g.LevelComplete() && !g.Won() {
+		for _, item := range g.Items() {
+			grid[item.Pos.Y][item.Pos.X] = itemStyle(item.Kind).Render(string(itemGlyph(item.Kind)))
+		}
+	}
+
+	for i, segment := range g.Snake() {
+		if segment.X < 0 || segment.X >= snake.Width || segment.Y < 0 || segment.Y >= snake.Height {
+			continue
+		}
+		if i == 0 {
+			grid[segment.Y][segment.X] = styles.SnakeHeadStyle.Render("◉")
+		} else {
+			grid[segment.Y][segment.X] = styles.SnakeStyle.Render("●")
+		}
+	}
+
+	return renderGrid(grid)
+}
+
+// renderGrid frames a board of rendered cells
+func renderGrid(grid [snake.Height][snake.Width]string) string {
+	var rows []string
+	rows = append(rows, styles.BorderStyle.Render("┌"+strings.Repeat("─", snake.Width)+"┐"))
+	for y := range snake.Height {
+		rows = append(rows, styles.BorderStyle.Render("│")+strings.Join(grid[y][:], "")+styles.BorderStyle.Render("│"))
+	}
+	rows = append(rows, styles.BorderStyle.Render("└"+strings.Repeat("─", snake.Width)+"┘"))
+
+	return strings.Join(rows, "\n")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/games/snake"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+func renderVersus(v *snake.Versus) string {
+	var grid [snake.Height][snake.Width]string
+	for y := range snake.Height {
+		for x := range snake.Width {
+			grid[y][x] = " "
+		}
+	}
+
+	playerStyles := [2]lipgloss.Style{styles.Player1Style, styles.Player2Style}
+	for i, p := range v.Players() {
+		body, head := snakeGlyphs(i)
+		for j, segment := range p.Body {
+			glyph := body
+			if j == 0 {
+				glyph = head
+			}
+			grid[segment.Y][segment.X] = playerStyles[i].Render(glyph)
+		}
+	}
+	for _, crash := range v.Crashes() {
+		if crash.X >= 0 && crash.X < snake.Width && crash.Y >= 0 && crash.Y < snake.Height {
+			grid[crash.Y][crash.X] = styles.ErrorStyle.Render("✕")
+		}
+	}
+
+	return renderGrid(grid)
+}
+
+// snakeGlyphs returns the body and head of a player's snake. In
+// colorblind mode the second snake is square, so the two don't differ
+// only in color.
+func snakeGlyphs(player int) (body, head string) {
+	if player == 1 && styles.Colorblind() {
+		return "■", "▣"
+	}
+	return "●", "◉"
+}
+
+func renderVersusScore(v *snake.Versus) string {
+	players := v.Players()
+	body1, _ := snakeGlyphs(0)
+	body2, _ := snakeGlyphs(1)
+	return fmt.Sprintf("%s %d – %d %s   Round %d (best of %d)",
+		styles.Player1Style.Render(body1+" "+players[0].Name),
+		players[0].Wins,
+		players[1].Wins,
+		styles.Player2Style.Render(players[1].Name+" "+body2),
+		len(v.Rounds())+boolToInt(!v.RoundOver()),
+		v.BestOf(),
+	)
+}
+
+// renderVersusSummary lists every round played so far and the match
+// standing
+func renderVersusSummary(v *snake.Versus) string {
+	playerStyles := [2]lipgloss.Style{styles.Player1Style, styles.Player2Style}
+	players := v.Players()
+
+	var lines []string
+	if v.MatchOver() {
+		winner := 0
+		if players[1].Wins > players[0].Wins {
+			winner = 1
+		}
+		lines = append(lines, playerStyles[winner].Render(fmt.Sprintf("%s wins the match %d – %d!",
+			players[winner].Name, players[winner].Wins, players[1-winner].Wins)))
+	} else {
+		lines = append(lines, styles.TitleStyle.Render(fmt.Sprintf("Round %d over", len(v.Rounds()))))
+	}
+
+	for i, round := range v.Rounds() {
+		var outcome string
+		if round.Winner < 0 {
+			outcome = styles.WarningStyle.Render("Draw")
+		} else {
+			outcome = playerStyles[round.Winner].Render(players[round.Winner].Name + " won")
+		}
+
+		var causes []string
+		for j, cause := range round.Causes {
+			if cause != "" {
+				causes = append(causes, fmt.Sprintf("%s %s", players[j].Name, cause))
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("Round %d  %s  %s", i+1, outcome,
+			styles.MenuItemStyle.Render("("+strings.Join(causes, ", ")+")")))
+	}
+
+	return styles.SidebarStyle.Width(0).Render(strings.Join(lines, "\n"))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
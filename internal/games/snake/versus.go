@@ -0,0 +1,278 @@
+package snake
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+// Versus is a Tron-style match between two snakes. Snakes never shrink,
+// so every move leaves a trail behind; the last snake standing takes
+// the round. Players steer with actions such as "p1:up" and "p2:left".
+type Versus struct {
+	players   [2]*Player
+	bestOf    int
+	rounds    []Round
+	roundOver bool
+	crashes   []Position
+}
+
+var (
+	_ engine.Realtime    = (*Versus)(nil)
+	_ engine.Multiplayer = (*Versus)(nil)
+)
+
+// Player is one of the snakes of a versus match
+type Player struct {
+	Name      string
+	Body      []Position // head first
+	Wins      int
+	Crashed   bool
+	Cause     string // why the player crashed this round
+	direction Direction
+	next      Direction
+}
+
+// Round is the result of a finished round
+type Round struct {
+	Winner int // index of the winning player, -1 for a draw
+	Causes [2]string
+}
+
+// NewVersus starts a match won by whoever takes the majority of bestOf
+// rounds
+func NewVersus(bestOf int) *Versus {
+	v := &Versus{
+		players: [2]*Player{
+			{Name: "Player 1"},
+			{Name: "Player 2"},
+		},
+		bestOf: bestOf,
+	}
+	v.startRound()
+	return v
+}
+
+// TurnPlayer returns the action steering player i, counted from 0, in
+// direction d
+func TurnPlayer(i int, d Direction) engine.Action {
+	return engine.Action(fmt.Sprintf("p%d:%s", i+1, d))
+}
+
+func (v *Versus) startRound() {
+	spawns := [2]Level{
+		{Spawn: Position{5, Height / 2}, Direction: Right},
+		{Spawn: Position{Width - 6, Height / 2}, Direction: Left},
+	}
+	for i, p := range v.players {
+		p.Body = spawns[i].startingBody()
+		p.direction = spawns[i].Direction
+		p.next = spawns[i].Direction
+		p.Crashed = false
+		p.Cause = ""
+	}
+	v.crashes = nil
+	v.roundOver = false
+}
+
+// winsNeeded is the number of rounds that decides the match
+func (v *Versus) winsNeeded() int {
+	return v.bestOf/2 + 1
+}
+
+// MatchOver reports whether a player has won enough rounds
+func (v *Versus) MatchOver() bool {
+	for _, p := range v.players {
+		if p.Wins >= v.winsNeeded() {
+			return true
+		}
+	}
+	return false
+}
+
+// Step steers a player, or starts the next round once one is over
+func (v *Versus) Step(action engine.Action) error {
+	if action == Next && v.roundOver && !v.MatchOver() {
+		v.startRound()
+		return nil
+	}
+
+	illegal := fmt.Errorf("%w: %s", engine.ErrIllegal, action)
+	if v.roundOver {
+		return illegal
+	}
+
+	player, dir, _ := strings.Cut(string(action), ":")
+	i := slices.Index([]string{"p1", "p2"}, player)
+	d, err := parseDirection(dir)
+	if i < 0 || err != nil || d == v.players[i].direction.opposite() {
+		return illegal
+	}
+	v.players[i].next = d
+	return nil
+}
+
+func (v *Versus) Legal() []engine.Action {
+	switch {
+	case v.MatchOver():
+		return nil
+	case v.roundOver:
+		return []engine.Action{Next}
+	}
+
+	var actions []engine.Action
+	for i, p := range v.players {
+		for _, d := range directions {
+			if d != p.direction.opposite() {
+				actions = append(actions, TurnPlayer(i, d))
+			}
+		}
+	}
+	return actions
+}
+
+func (v *Versus) PlayerCount() int { return 2 }
+
+func (v *Versus) PlayerName(player int) string { return v.players[player].Name }
+
+// LegalFor lists the turns of one player, and Next for player 0 once a
+// round is over
+func (v *Versus) LegalFor(player int) []engine.Action {
+	prefix := fmt.Sprintf("p%d:", player+1)
+	var actions []engine.Action
+	for _, action := range v.Legal() {
+		if strings.HasPrefix(string(action), prefix) || (action == Next && player == 0) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// Result scores the match by the rounds won by player 1
+func (v *Versus) Result() engine.Result {
+	result := engine.Result{Score: v.players[0].Wins}
+	if !v.MatchOver() {
+		return result
+	}
+
+	result.Over = true
+	result.Outcome = engine.OutcomeLoss
+	winner := v.players[1]
+	if v.players[0].Wins > v.players[1].Wins {
+		result.Outcome = engine.OutcomeWin
+		winner = v.players[0]
+	}
+	result.Winner = winner.Name
+	return result
+}
+
+// Tick moves both snakes one cell
+func (v *Versus) Tick() {
+	if v.roundOver {
+		return
+	}
+
+	var heads [2]Position
+	for i, p := range v.players {
+		p.direction = p.next
+		heads[i] = p.Body[0].move(p.direction)
+	}
+
+	// Heads meeting in one cell or passing through each other
+	headOn := heads[0] == heads[1] ||
+		(heads[0] == v.players[1].Body[0] && heads[1] == v.players[0].Body[0])
+
+	if headOn {
+		for _, p := range v.players {
+			p.crash("head-on collision")
+		}
+		v.crashes = append(v.crashes, heads[0])
+	} else {
+		for i, p := range v.players {
+			if cause := v.collision(i, heads[i]); cause != "" {
+				p.crash(cause)
+				v.crashes = append(v.crashes, heads[i])
+			}
+		}
+	}
+
+	for i, p := range v.players {
+		if !p.Crashed {
+			p.Body = append([]Position{heads[i]}, p.Body...)
+		}
+	}
+
+	v.endRoundIfDecided()
+}
+
+func (v *Versus) Interval() time.Duration {
+	return tickRate
+}
+
+// collision reports why player i cannot move its head to p, if it can't
+func (v *Versus) collision(i int, p Position) string {
+	if !inBounds(p) {
+		return "hit the wall"
+	}
+	for j, other := range v.players {
+		for _, segment := range other.Body {
+			if segment != p {
+				continue
+			}
+			if i == j {
+				return "ran into itself"
+			}
+			return "ran into " + other.Name
+		}
+	}
+	return ""
+}
+
+func (p *Player) crash(cause string) {
+	p.Crashed = true
+	p.Cause = cause
+}
+
+func (v *Versus) endRoundIfDecided() {
+	crashed0, crashed1 := v.players[0].Crashed, v.players[1].Crashed
+	if !crashed0 && !crashed1 {
+		return
+	}
+
+	result := Round{Winner: -1}
+	for i, p := range v.players {
+		result.Causes[i] = p.Cause
+	}
+	switch {
+	case crashed1 && !crashed0:
+		result.Winner = 0
+	case crashed0 && !crashed1:
+		result.Winner = 1
+	}
+	if result.Winner >= 0 {
+		v.players[result.Winner].Wins++
+	}
+
+	v.rounds = append(v.rounds, result)
+	v.roundOver = true
+}
+
+// Players returns both players, player 1 first
+func (v *Versus) Players() [2]Player {
+	return [2]Player{*v.players[0], *v.players[1]}
+}
+
+// Rounds returns the finished rounds
+func (v *Versus) Rounds() []Round { return v.rounds }
+
+// RoundOver reports whether the current round has been decided
+func (v *Versus) RoundOver() bool { return v.roundOver }
+
+// Crashes returns where snakes crashed this round
+func (v *Versus) Crashes() []Position { return v.crashes }
+
+// BestOf returns the length of the match in rounds
+func (v *Versus) BestOf() int { return v.bestOf }
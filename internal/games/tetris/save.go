@@ -0,0 +1,93 @@
+package tetris
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// savedGame is a game as encoded to JSON. The starting level is set by
+// NewGame.
+type savedGame struct {
+	Board   [Height][Width]int `json:"board"`
+	Current savedPiece         `json:"current"`
+	Next    savedPiece         `json:"next"`
+	Bag     []int              `json:"bag"`
+	Score   int                `json:"score"`
+	Level   int                `json:"level"`
+	Lines   int                `json:"lines"`
+}
+
+type savedPiece struct {
+	Shape [][]int `json:"shape"`
+	X     int     `json:"x"`
+	Y     int     `json:"y"`
+	Color int     `json:"color"`
+}
+
+func newSavedPiece(p Piece) savedPiece {
+	return savedPiece{Shape: p.Shape, X: p.X, Y: p.Y, Color: p.Color}
+}
+
+func (s savedPiece) piece() (Piece, error) {
+	if s.Color < 1 || s.Color >= len(shapes) || len(s.Shape) != len(shapes[s.Color]) {
+		return Piece{}, fmt.Errorf("unknown piece")
+	}
+	for _, row := range s.Shape {
+		if len(row) != len(s.Shape) {
+			return Piece{}, fmt.Errorf("piece is not square")
+		}
+	}
+	return Piece{Shape: s.Shape, X: s.X, Y: s.Y, Color: s.Color}, nil
+}
+
+func (g *Game) MarshalJSON() ([]byte, error) {
+	return json.Marshal(savedGame{
+		Board:   g.board,
+		Current: newSavedPiece(g.currentPiece),
+		Next:    newSavedPiece(g.nextPiece),
+		Bag:     g.bag,
+		Score:   g.score,
+		Level:   g.level,
+		Lines:   g.lines,
+	})
+}
+
+// UnmarshalJSON restores a game into one created by NewGame, which sets
+// the starting level and the random source
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var state savedGame
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	current, err := state.Current.piece()
+	if err != nil {
+		return err
+	}
+	next, err := state.Next.piece()
+	if err != nil {
+		return err
+	}
+	for _, row := range state.Board {
+		for _, color := range row {
+			if color < 0 || color >= len(shapes) {
+				return fmt.Errorf("unknown block on the board")
+			}
+		}
+	}
+	for _, color := range state.Bag {
+		if color < 1 || color >= len(shapes) {
+			return fmt.Errorf("unknown piece in bag")
+		}
+	}
+
+	g.board = state.Board
+	g.currentPiece = current
+	g.nextPiece = next
+	g.bag = state.Bag
+	g.score = state.Score
+	g.level = max(state.Level, 1)
+	g.lines = state.Lines
+	g.over = false
+	return nil
+}
@@ -1,217 +1,317 @@
+// Package tetris is the tetris engine. The player moves the falling
+// piece with actions while Tick pulls it down at the pace of the level.
 package tetris
 
 import (
 	"fmt"
-	"strings"
+	"math/rand/v2"
+	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/jakmaz/arcade/internal/ui/styles"
+	"github.com/jakmaz/arcade/internal/engine"
 )
 
-type Model struct {
-	board         [20][10]int
-	currentPiece  Piece
-	nextPiece     Piece
-	score         int
-	level         int
-	lines         int
-	gameOver      bool
-	width, height int
+const (
+	Width  = 10
+	Height = 20
+)
+
+// Actions accepted by Step
+const (
+	Left       engine.Action = "left"
+	Right      engine.Action = "right"
+	Down       engine.Action = "down"        // soft drop by one row
+	Rotate     engine.Action = "rotate"      // clockwise
+	RotateBack engine.Action = "rotate_back" // counterclockwise
+	Drop       engine.Action = "drop"        // hard drop
+)
+
+var actions = []engine.Action{Left, Right, Down, Rotate, RotateBack, Drop}
+
+// Game is a game of marathon tetris
+type Game struct {
+	rng          *rand.Rand
+	board        [Height][Width]int
+	currentPiece Piece
+	nextPiece    Piece
+	bag          []int
+	score        int
+	startLevel   int
+	level        int
+	lines        int
+	over         bool
 }
 
+var _ engine.Realtime = (*Game)(nil)
+
+// Piece is a tetromino. The shape is a square matrix so that rotating it
+// keeps the piece roughly in place. Color numbers the tetromino from 1
+// to 7, the values blocks take on the board.
 type Piece struct {
-	shape [][]int
-	x, y  int
-	color int
-}
-
-type Position struct {
-	x, y int
-}
-
-func New() *Model {
-	m := &Model{
-		currentPiece: Piece{
-			shape: [][]int{
-				{1, 1, 1, 1},
-			},
-			x:     3,
-			y:     0,
-			color: 1,
-		},
-		nextPiece: Piece{
-			shape: [][]int{
-				{1, 1},
-				{1, 1},
-			},
-			x:     0,
-			y:     0,
-			color: 2,
-		},
-		score:    12450,
-		level:    3,
-		lines:    8,
-		gameOver: false,
-	}
-
-	for y := 17; y < 20; y++ {
-		for x := range 10 {
-			if x < 2 || x > 7 || y == 19 {
-				m.board[y][x] = (x+y)%7 + 1
-			}
-		}
-	}
+	Shape [][]int
+	X, Y  int
+	Color int
+}
 
-	return m
+// pieceNames are the tetromino names by color
+var pieceNames = []string{"", "I", "O", "T", "S", "Z", "J", "L"}
+
+// PieceName returns the name of the tetromino with the given color,
+// e.g. "T"
+func PieceName(color int) string {
+	return pieceNames[color]
 }
 
-func (m *Model) Init() tea.Cmd {
-	return nil
+var shapes = [][][]int{
+	nil,
+	{{0, 0, 0, 0}, {1, 1, 1, 1}, {0, 0, 0, 0}, {0, 0, 0, 0}}, // I
+	{{1, 1}, {1, 1}},                  // O
+	{{0, 1, 0}, {1, 1, 1}, {0, 0, 0}}, // T
+	{{0, 1, 1}, {1, 1, 0}, {0, 0, 0}}, // S
+	{{1, 1, 0}, {0, 1, 1}, {0, 0, 0}}, // Z
+	{{1, 0, 0}, {1, 1, 1}, {0, 0, 0}}, // J
+	{{0, 0, 1}, {1, 1, 1}, {0, 0, 0}}, // L
 }
 
-func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+// lineScores are the points for clearing 1-4 lines at once, per level
+var lineScores = []int{0, 100, 300, 500, 800}
+
+// NewGame starts a game at the given level, drawing pieces from rng
+func NewGame(rng *rand.Rand, level int) *Game {
+	level = max(level, 1)
+	g := &Game{
+		rng:        rng,
+		startLevel: level,
+		level:      level,
 	}
-	return m, nil
+	g.nextPiece = g.newPiece()
+	g.spawnPiece()
+	return g
 }
 
-func (m *Model) View() string {
-	title := styles.TitleStyle.Render("Tetris")
+func (g *Game) Step(action engine.Action) error {
+	if g.over {
+		return fmt.Errorf("%w: the game is over", engine.ErrIllegal)
+	}
 
-	gameArea := lipgloss.JoinHorizontal(lipgloss.Top,
-		m.renderBoard(),
-		"  ",
-		m.renderSidebar(),
-	)
+	switch action {
+	case Left:
+		g.move(-1, 0)
+	case Right:
+		g.move(1, 0)
+	case Down:
+		if g.move(0, 1) {
+			g.score++
+		}
+	case Rotate:
+		g.rotate(true)
+	case RotateBack:
+		g.rotate(false)
+	case Drop:
+		for g.move(0, 1) {
+			g.score += 2
+		}
+		g.lockPiece()
+	default:
+		return fmt.Errorf("%w: %s", engine.ErrIllegal, action)
+	}
+	return nil
+}
 
-	var status string
-	if m.gameOver {
-		status = styles.GameOverStyle.Render("Game Over!")
-	} else {
-		status = styles.SelectedItemStyle.Render("Playing...")
+// Legal lists every action while the game runs. Moves into a wall are
+// accepted and leave the piece where it is.
+func (g *Game) Legal() []engine.Action {
+	if g.over {
+		return nil
 	}
+	return actions
+}
 
-	help := styles.HelpStyle.Render("← → to move, ↓ to drop, ↑ to rotate, Space to hard drop, ESC to return to menu")
+func (g *Game) Result() engine.Result {
+	return engine.Result{Over: g.over, Score: g.score}
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		title,
-		"",
-		gameArea,
-		"",
-		status,
-		"",
-		help,
-	)
+// Tick moves the piece down one row, locking it when it lands
+func (g *Game) Tick() {
+	if g.over {
+		return
+	}
+	if !g.move(0, 1) {
+		g.lockPiece()
+	}
+}
 
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+// Interval is the gravity speed, getting faster with every level
+func (g *Game) Interval() time.Duration {
+	interval := 800*time.Millisecond - time.Duration(g.level-1)*70*time.Millisecond
+	return max(interval, 80*time.Millisecond)
 }
 
-func (m *Model) renderBoard() string {
-	colors := []lipgloss.Color{
-		"",
-		"#ff6b6b", // Red
-		"#4ecdc4", // Teal
-		"#45b7d1", // Blue
-		"#f9ca24", // Yellow
-		"#f0932b", // Orange
-		"#eb4d4b", // Dark Red
-		"#6c5ce7", // Purple
-	}
+func (g *Game) Level() int { return g.level }
+func (g *Game) Lines() int { return g.lines }
+
+// Next returns the piece that comes after the falling one
+func (g *Game) Next() Piece { return g.nextPiece }
 
-	var board [20][10]int
-	copy(board[:], m.board[:])
+// Board returns the color of every cell, 0 for empty ones, with the
+// falling piece drawn in while the game runs
+func (g *Game) Board() [Height][Width]int {
+	board := g.board
+	if g.over {
+		return board
+	}
 
-	for i, row := range m.currentPiece.shape {
+	for i, row := range g.currentPiece.Shape {
 		for j, cell := range row {
-			if cell != 0 {
-				boardY := m.currentPiece.y + i
-				boardX := m.currentPiece.x + j
-				if boardY >= 0 && boardY < 20 && boardX >= 0 && boardX < 10 {
-					board[boardY][boardX] = m.currentPiece.color
-				}
+			if cell == 0 {
+				continue
+			}
+			y, x := g.currentPiece.Y+i, g.currentPiece.X+j
+			if y >= 0 && y < Height && x >= 0 && x < Width {
+				board[y][x] = g.currentPiece.Color
 			}
 		}
 	}
+	return board
+}
+
+// newPiece draws the next tetromino from a shuffled bag of all seven,
+// so no piece is ever missing for long
+func (g *Game) newPiece() Piece {
+	if len(g.bag) == 0 {
+		g.bag = []int{1, 2, 3, 4, 5, 6, 7}
+		g.rng.Shuffle(len(g.bag), func(i, j int) {
+			g.bag[i], g.bag[j] = g.bag[j], g.bag[i]
+		})
+	}
+	color := g.bag[0]
+	g.bag = g.bag[1:]
+
+	shape := make([][]int, len(shapes[color]))
+	for i, row := range shapes[color] {
+		shape[i] = append([]int(nil), row...)
+	}
 
-	var rows []string
+	return Piece{Shape: shape, Color: color}
+}
 
-	topBorder := styles.BorderStyle.Render("┌" + strings.Repeat("─", 20) + "┐")
-	rows = append(rows, topBorder)
+// spawnPiece brings the next piece onto the board. The game is over
+// when there is no room for it.
+func (g *Game) spawnPiece() {
+	g.currentPiece = g.nextPiece
+	g.currentPiece.X = (Width - len(g.currentPiece.Shape)) / 2
+	g.currentPiece.Y = 0
+	if len(g.currentPiece.Shape) == 4 {
+		g.currentPiece.Y = -1 // the I piece's first row is empty
+	}
+	g.nextPiece = g.newPiece()
 
-	for y := range 20 {
-		var rowContent strings.Builder
-		rowContent.WriteString(styles.BorderStyle.Render("│"))
+	if g.collides(g.currentPiece) {
+		g.over = true
+	}
+}
 
-		for x := range 10 {
-			cell := board[y][x]
+func (g *Game) collides(p Piece) bool {
+	for i, row := range p.Shape {
+		for j, cell := range row {
 			if cell == 0 {
-				rowContent.WriteString("  ")
-			} else {
-				blockStyle := lipgloss.NewStyle().
-					Background(colors[cell]).
-					Foreground(colors[cell])
-				rowContent.WriteString(blockStyle.Render("██"))
+				continue
+			}
+			x, y := p.X+j, p.Y+i
+			if x < 0 || x >= Width || y >= Height {
+				return true
+			}
+			if y >= 0 && g.board[y][x] != 0 {
+				return true
 			}
 		}
-		rowContent.WriteString(styles.BorderStyle.Render("│"))
-		rows = append(rows, rowContent.String())
 	}
-
-	bottomBorder := styles.BorderStyle.Render("└" + strings.Repeat("─", 20) + "┘")
-	rows = append(rows, bottomBorder)
-
-	return strings.Join(rows, "\n")
+	return false
 }
 
-func (m *Model) renderSidebar() string {
-	stats := fmt.Sprintf("Score: %d\n\nLevel: %d\n\nLines: %d", m.score, m.level, m.lines)
-
-	nextPieceTitle := styles.SelectedItemStyle.Render("Next:")
-	nextPiece := m.renderNextPiece()
-
-	content := lipgloss.JoinVertical(lipgloss.Left,
-		stats,
-		"",
-		nextPieceTitle,
-		nextPiece,
-	)
-
-	return styles.SidebarStyle.Render(content)
+// move shifts the current piece and reports whether it fit
+func (g *Game) move(dx, dy int) bool {
+	moved := g.currentPiece
+	moved.X += dx
+	moved.Y += dy
+	if g.collides(moved) {
+		return false
+	}
+	g.currentPiece = moved
+	return true
 }
 
-func (m *Model) renderNextPiece() string {
-	colors := []lipgloss.Color{
-		"",
-		"#ff6b6b", // Red
-		"#4ecdc4", // Teal
-		"#45b7d1", // Blue
-		"#f9ca24", // Yellow
-		"#f0932b", // Orange
-		"#eb4d4b", // Dark Red
-		"#6c5ce7", // Purple
+// rotate turns the current piece, nudging it sideways when it would
+// overlap a wall or another block
+func (g *Game) rotate(clockwise bool) {
+	size := len(g.currentPiece.Shape)
+	rotated := g.currentPiece
+	rotated.Shape = make([][]int, size)
+	for i := range size {
+		rotated.Shape[i] = make([]int, size)
+		for j := range size {
+			if clockwise {
+				rotated.Shape[i][j] = g.currentPiece.Shape[size-1-j][i]
+			} else {
+				rotated.Shape[i][j] = g.currentPiece.Shape[j][size-1-i]
+			}
+		}
 	}
 
-	var rows []string
-	for _, row := range m.nextPiece.shape {
-		var rowContent strings.Builder
-		for _, cell := range row {
+	for _, kick := range []int{0, -1, 1, -2, 2} {
+		candidate := rotated
+		candidate.X += kick
+		if !g.collides(candidate) {
+			g.currentPiece = candidate
+			return
+		}
+	}
+}
+
+func (g *Game) lockPiece() {
+	for i, row := range g.currentPiece.Shape {
+		for j, cell := range row {
 			if cell == 0 {
-				rowContent.WriteString("  ")
-			} else {
-				blockStyle := lipgloss.NewStyle().
-					Background(colors[m.nextPiece.color]).
-					Foreground(colors[m.nextPiece.color])
-				rowContent.WriteString(blockStyle.Render("██"))
+				continue
 			}
+			y, x := g.currentPiece.Y+i, g.currentPiece.X+j
+			if y < 0 {
+				// Locked above the visible board
+				g.over = true
+				return
+			}
+			g.board[y][x] = g.currentPiece.Color
 		}
-		rows = append(rows, rowContent.String())
 	}
 
-	return strings.Join(rows, "\n")
+	g.clearLines()
+	g.spawnPiece()
 }
 
-type tickMsg struct{}
+func (g *Game) clearLines() {
+	cleared := 0
+	for y := Height - 1; y >= 0; y-- {
+		full := true
+		for x := range Width {
+			if g.board[y][x] == 0 {
+				full = false
+				break
+			}
+		}
+		if !full {
+			continue
+		}
+
+		copy(g.board[1:y+1], g.board[0:y])
+		g.board[0] = [Width]int{}
+		cleared++
+		y++ // check the row that moved down into this one
+	}
+
+	if cleared == 0 {
+		return
+	}
+
+	g.score += lineScores[cleared] * g.level
+	g.lines += cleared
+	g.level = g.startLevel + g.lines/10
+}
@@ -0,0 +1,200 @@
+package tetris
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+func newTestGame(level int) *Game {
+	return NewGame(rand.New(rand.NewPCG(1, 2)), level)
+}
+
+// fillRow fills row y with blocks of color, leaving the columns in gaps
+// empty
+func fillRow(g *Game, y, color int, gaps ...int) {
+	for x := range Width {
+		g.board[y][x] = color
+	}
+	for _, x := range gaps {
+		g.board[y][x] = 0
+	}
+}
+
+// blocks counts the filled cells of the board
+func blocks(g *Game) int {
+	n := 0
+	for _, row := range g.board {
+		for _, cell := range row {
+			if cell != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+var (
+	horizontalI = [][]int{{1, 1, 1, 1}}
+	verticalI   = [][]int{{1}, {1}, {1}, {1}}
+)
+
+func TestLineClears(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(g *Game)
+		piece   Piece
+		level   int
+		cleared int
+		score   int
+		blocks  int // left on the board
+	}{
+		{
+			name:  "no line",
+			setup: func(g *Game) { fillRow(g, 19, 2, 0, 1, 2, 3, 4) },
+			piece: Piece{Shape: horizontalI, X: 0, Y: 19, Color: 1},
+			level: 1, cleared: 0, score: 0, blocks: 9,
+		},
+		{
+			name:  "single",
+			setup: func(g *Game) { fillRow(g, 19, 2, 0, 1, 2, 3) },
+			piece: Piece{Shape: horizontalI, X: 0, Y: 19, Color: 1},
+			level: 1, cleared: 1, score: 100, blocks: 0,
+		},
+		{
+			name: "double",
+			setup: func(g *Game) {
+				fillRow(g, 18, 2, 9)
+				fillRow(g, 19, 2, 9)
+			},
+			piece: Piece{Shape: verticalI, X: 9, Y: 16, Color: 1},
+			level: 1, cleared: 2, score: 300, blocks: 2,
+		},
+		{
+			name: "triple",
+			setup: func(g *Game) {
+				for y := 17; y < Height; y++ {
+					fillRow(g, y, 2, 9)
+				}
+			},
+			piece: Piece{Shape: verticalI, X: 9, Y: 16, Color: 1},
+			level: 1, cleared: 3, score: 500, blocks: 1,
+		},
+		{
+			name: "tetris",
+			setup: func(g *Game) {
+				for y := 16; y < Height; y++ {
+					fillRow(g, y, 2, 9)
+				}
+			},
+			piece: Piece{Shape: verticalI, X: 9, Y: 16, Color: 1},
+			level: 1, cleared: 4, score: 800, blocks: 0,
+		},
+		{
+			name: "tetris on level 3",
+			setup: func(g *Game) {
+				for y := 16; y < Height; y++ {
+					fillRow(g, y, 2, 0)
+				}
+			},
+			piece: Piece{Shape: verticalI, X: 0, Y: 16, Color: 1},
+			level: 3, cleared: 4, score: 2400, blocks: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(tt.level)
+			tt.setup(g)
+			g.currentPiece = tt.piece
+			g.lockPiece()
+
+			if g.Lines() != tt.cleared {
+				t.Errorf("Lines() = %d, want %d", g.Lines(), tt.cleared)
+			}
+			if got := g.Result().Score; got != tt.score {
+				t.Errorf("score = %d, want %d", got, tt.score)
+			}
+			if got := blocks(g); got != tt.blocks {
+				t.Errorf("%d blocks left, want %d", got, tt.blocks)
+			}
+		})
+	}
+}
+
+// Rows above cleared ones move down, keeping their blocks
+func TestLineClearsShiftRows(t *testing.T) {
+	g := newTestGame(1)
+	fillRow(g, 19, 2, 0)
+	fillRow(g, 18, 3, 0, 5)
+	fillRow(g, 17, 4, 0)
+	g.board[15][7] = 5
+	g.currentPiece = Piece{Shape: verticalI, X: 0, Y: 16, Color: 1}
+	g.lockPiece()
+
+	if g.Lines() != 2 {
+		t.Fatalf("Lines() = %d, want 2", g.Lines())
+	}
+	var want [Height][Width]int
+	for x := range Width {
+		want[19][x] = 3
+	}
+	want[19][0] = 1 // the I piece filled the gap
+	want[19][5] = 0
+	want[18][0] = 1 // the top of the I piece
+	want[17][7] = 5
+	if g.board != want {
+		t.Errorf("board after clearing rows 17 and 19:\n%v\nwant\n%v", g.board, want)
+	}
+}
+
+func TestLevelUp(t *testing.T) {
+	g := newTestGame(2)
+	g.lines = 9
+	fillRow(g, 18, 2, 9)
+	fillRow(g, 19, 2, 9)
+	g.currentPiece = Piece{Shape: verticalI, X: 9, Y: 16, Color: 1}
+	g.lockPiece()
+
+	if g.Lines() != 11 || g.Level() != 3 {
+		t.Errorf("lines %d, level %d; want 11 and 3", g.Lines(), g.Level())
+	}
+	// Lines are scored at the level they were cleared on
+	if got := g.Result().Score; got != 600 {
+		t.Errorf("score = %d, want 600", got)
+	}
+}
+
+func TestHardDrop(t *testing.T) {
+	g := newTestGame(1)
+	fillRow(g, 19, 2, 0, 1, 2, 3)
+	g.currentPiece = Piece{Shape: horizontalI, X: 0, Y: 0, Color: 1}
+	if err := g.Step(Drop); err != nil {
+		t.Fatal(err)
+	}
+	// Two points per row dropped, then the single
+	if got, want := g.Result().Score, 19*2+100; got != want {
+		t.Errorf("score = %d, want %d", got, want)
+	}
+	if g.Lines() != 1 {
+		t.Errorf("Lines() = %d, want 1", g.Lines())
+	}
+}
+
+func TestGameOver(t *testing.T) {
+	g := newTestGame(1)
+	for y := range Height {
+		fillRow(g, y, 2, 0)
+	}
+	g.spawnPiece()
+	if !g.Result().Over {
+		t.Fatal("game not over with no room for a piece")
+	}
+	if err := g.Step(Left); !errors.Is(err, engine.ErrIllegal) {
+		t.Errorf("Step after the game = %v, want ErrIllegal", err)
+	}
+	if g.Legal() != nil {
+		t.Errorf("Legal() = %v after the game", g.Legal())
+	}
+}
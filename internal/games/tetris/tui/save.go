@@ -0,0 +1,19 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SaveState writes the engine's state. The starting level comes from the
+// game's options.
+func (m *Model) SaveState() ([]byte, error) {
+	return json.Marshal(m.game)
+}
+
+func (m *Model) LoadState(data []byte) error {
+	if err := json.Unmarshal(data, m.game); err != nil {
+		return fmt.Errorf("invalid tetris save: %w", err)
+	}
+	return nil
+}
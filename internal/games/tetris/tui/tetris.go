@@ -0,0 +1,230 @@
+// Package tui plays the tetris engine in the terminal
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/games/tetris"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+func init() {
+	core.Register(core.GameInfo{
+		ID:          "tetris",
+		Name:        "Tetris",
+		Description: "Block puzzle game",
+		Options: []core.OptionInfo{
+			{Key: "level", Values: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, Default: "1", Help: "starting level"},
+		},
+		New: func(options core.Options) core.Game { return New(options) },
+	})
+}
+
+type Model struct {
+	game          *tetris.Game
+	paused        bool
+	width, height int
+}
+
+func New(options core.Options) *Model {
+	level, err := strconv.Atoi(options.Get("level", "1"))
+	if err != nil {
+		level = 1
+	}
+	return &Model{game: tetris.NewGame(options.Rand(), level)}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// keyActions maps keys to the engine's actions
+var keyActions = map[string]engine.Action{
+	"left":  tetris.Left,
+	"right": tetris.Right,
+	"down":  tetris.Down,
+	"up":    tetris.Rotate,
+	"x":     tetris.Rotate,
+	"z":     tetris.RotateBack,
+	" ":     tetris.Drop,
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case core.TickMsg:
+		if !m.paused {
+			m.publishLines(m.game.Tick)
+		}
+
+	case tea.KeyMsg:
+		if m.paused {
+			return m, nil
+		}
+		if action, ok := keyActions[msg.String()]; ok {
+			m.publishLines(func() { m.game.Step(action) })
+		}
+	}
+	return m, nil
+}
+
+// publishLines runs play and announces the lines it cleared
+func (m *Model) publishLines(play func()) {
+	lines := m.game.Lines()
+	play()
+	if cleared := m.game.Lines() - lines; cleared > 0 {
+		core.Publish(core.Event{Game: "tetris", Name: "lines_cleared", Value: cleared})
+	}
+}
+
+func (m *Model) Pause()  { m.paused = true }
+func (m *Model) Resume() { m.paused = false }
+
+func (m *Model) Engine() engine.Engine {
+	return m.game
+}
+
+func (m *Model) State() core.State {
+	result := m.game.Result()
+	return core.State{
+		Mode:   "marathon",
+		Score:  result.Score,
+		Paused: m.paused,
+		Over:   result.Over,
+		Details: map[string]int{
+			"lines": m.game.Lines(),
+			"level": m.game.Level(),
+		},
+	}
+}
+
+func (m *Model) KeyBindings() []core.KeyBinding {
+	return []core.KeyBinding{
+		{Keys: "← →", Help: "move"},
+		{Keys: "↓", Help: "drop"},
+		{Keys: "↑", Help: "rotate"},
+		{Keys: "Space", Help: "hard drop"},
+	}
+}
+
+// TickInterval is the gravity speed of the current level
+func (m *Model) TickInterval() time.Duration {
+	return m.game.Interval()
+}
+
+func (m *Model) View() string {
+	title := styles.TitleStyle.Render("Tetris")
+
+	gameArea := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.renderBoard(),
+		"  ",
+		m.renderSidebar(),
+	)
+
+	var status string
+	if m.game.Result().Over {
+		status = styles.GameOverStyle.Render("Game Over!")
+	} else {
+		status = styles.SelectedItemStyle.Render("Playing...")
+	}
+
+	help := styles.HelpStyle.Render(core.HelpLine(m.KeyBindings()))
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		gameArea,
+		"",
+		status,
+		"",
+		help,
+	)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m *Model) renderBoard() string {
+	board := m.game.Board()
+
+	var rows []string
+
+	topBorder := styles.BorderStyle.Render("┌" + strings.Repeat("─", tetris.Width*2) + "┐")
+	rows = append(rows, topBorder)
+
+	for y := range tetris.Height {
+		var rowContent strings.Builder
+		rowContent.WriteString(styles.BorderStyle.Render("│"))
+
+		for x := range tetris.Width {
+			cell := board[y][x]
+			if cell == 0 {
+				rowContent.WriteString("  ")
+			} else {
+				rowContent.WriteString(block(cell))
+			}
+		}
+		rowContent.WriteString(styles.BorderStyle.Render("│"))
+		rows = append(rows, rowContent.String())
+	}
+
+	bottomBorder := styles.BorderStyle.Render("└" + strings.Repeat("─", tetris.Width*2) + "┘")
+	rows = append(rows, bottomBorder)
+
+	return strings.Join(rows, "\n")
+}
+
+func (m *Model) renderSidebar() string {
+	stats := fmt.Sprintf("Score: %d\n\nLevel: %d\n\nLines: %d", m.game.Result().Score, m.game.Level(), m.game.Lines())
+
+	nextPieceTitle := styles.SelectedItemStyle.Render("Next:")
+	nextPiece := m.renderNextPiece()
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		stats,
+		"",
+		nextPieceTitle,
+		nextPiece,
+	)
+
+	return styles.SidebarStyle.Render(content)
+}
+
+func (m *Model) renderNextPiece() string {
+	next := m.game.Next()
+
+	var rows []string
+	for _, row := range next.Shape {
+		var rowContent strings.Builder
+		empty := true
+		for _, cell := range row {
+			if cell == 0 {
+				rowContent.WriteString("  ")
+			} else {
+				empty = false
+				rowContent.WriteString(block(next.Color))
+			}
+		}
+		if !empty {
+			rows = append(rows, rowContent.String())
+		}
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// block draws a block in the theme's color for its piece, and in
+// colorblind mode with the piece's pattern
+func block(color int) string {
+	piece := tetris.PieceName(color)
+	return styles.GetStyles().TetrisPieceStyle(piece).Render(styles.TetrisBlock(piece))
+}
@@ -1,100 +1,267 @@
+// Package tictactoe is the tic-tac-toe engine. Cells are named by
+// column a-c and row 1-3 counted from the top, so "b2" is the center.
 package tictactoe
 
 import (
-	"strings"
+	"encoding/json"
+	"fmt"
 
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/jakmaz/arcade/internal/ui/styles"
+	"github.com/jakmaz/arcade/internal/engine"
 )
 
-func New() *Model {
-	m := &Model{
+// Empty marks a free cell
+const Empty = ' '
+
+// Game is a game of tic-tac-toe, X moving first
+type Game struct {
+	board  [3][3]rune
+	turn   rune
+	winner rune
+	over   bool
+}
+
+var (
+	_ engine.TurnBased = (*Game)(nil)
+	_ engine.Solver    = (*Game)(nil)
+)
+
+func NewGame() *Game {
+	return &Game{
 		board: [3][3]rune{
-			{'X', ' ', 'O'},
-			{' ', 'X', ' '},
-			{'O', ' ', 'X'},
+			{Empty, Empty, Empty},
+			{Empty, Empty, Empty},
+			{Empty, Empty, Empty},
 		},
-		turn:    'X',
-		cursorX: 1,
-		cursorY: 1,
+		turn: 'X',
 	}
-	return m
 }
 
-type Model struct {
-	board            [3][3]rune
-	turn             rune
-	cursorX, cursorY int
-	width, height    int
+// Cell returns the action placing a mark at column x and row y
+func Cell(x, y int) engine.Action {
+	return engine.Action(fmt.Sprintf("%c%d", 'a'+x, y+1))
+}
+
+func parseCell(action engine.Action) (x, y int, ok bool) {
+	if len(action) != 2 {
+		return 0, 0, false
+	}
+	x, y = int(action[0]-'a'), int(action[1]-'1')
+	return x, y, x >= 0 && x < 3 && y >= 0 && y < 3
 }
 
-func (m Model) Init() tea.Cmd {
+// Step puts the mark of the player to move in a free cell and passes
+// the turn
+func (g *Game) Step(action engine.Action) error {
+	x, y, ok := parseCell(action)
+	if !ok || g.over || g.board[y][x] != Empty {
+		return fmt.Errorf("%w: %s", engine.ErrIllegal, action)
+	}
+
+	g.board[y][x] = g.turn
+	if winner := winnerOf(g.board); winner != 0 {
+		g.winner = winner
+		g.over = true
+	} else if boardFull(g.board) {
+		g.over = true
+	}
+
+	g.turn = opponent(g.turn)
 	return nil
 }
 
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+func (g *Game) Legal() []engine.Action {
+	if g.over {
+		return nil
 	}
-	return m, nil
+	var actions []engine.Action
+	for y := range 3 {
+		for x := range 3 {
+			if g.board[y][x] == Empty {
+				actions = append(actions, Cell(x, y))
+			}
+		}
+	}
+	return actions
 }
 
-func (m Model) View() string {
-	title := styles.TitleStyle.Render("Tic-Tac-Toe")
+func (g *Game) Result() engine.Result {
+	result := engine.Result{Over: g.over}
+	if !g.over {
+		return result
+	}
 
-	board := m.renderBoard()
+	switch g.winner {
+	case 'X':
+		result.Outcome = engine.OutcomeWin
+	case 'O':
+		result.Outcome = engine.OutcomeLoss
+	default:
+		result.Outcome = engine.OutcomeDraw
+	}
+	if g.winner != 0 {
+		result.Winner = string(g.winner)
+	}
+	return result
+}
 
-	currentPlayer := styles.SelectedItemStyle.Render("Current Player: " + string(m.turn))
+// PlayerCount is two, X being player 0
+func (g *Game) PlayerCount() int { return 2 }
 
-	help := styles.HelpStyle.Render("↑ ↓ ← → to move, Enter to place, ESC to return to menu")
+func (g *Game) ToMove() int {
+	if g.turn == 'X' {
+		return 0
+	}
+	return 1
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		title,
-		"",
-		board,
-		"",
-		currentPlayer,
-		"",
-		help,
-	)
+func (g *Game) PlayerName(player int) string {
+	if player == 0 {
+		return "X"
+	}
+	return "O"
+}
+
+// At returns the mark at column x and row y, or Empty
+func (g *Game) At(x, y int) rune { return g.board[y][x] }
+
+// Turn returns the mark of the player to move
+func (g *Game) Turn() rune { return g.turn }
+
+// Winner returns the mark with three in a row, or 0
+func (g *Game) Winner() rune { return g.winner }
 
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+var lines = [8][3][2]int{
+	{{0, 0}, {1, 0}, {2, 0}},
+	{{0, 1}, {1, 1}, {2, 1}},
+	{{0, 2}, {1, 2}, {2, 2}},
+	{{0, 0}, {0, 1}, {0, 2}},
+	{{1, 0}, {1, 1}, {1, 2}},
+	{{2, 0}, {2, 1}, {2, 2}},
+	{{0, 0}, {1, 1}, {2, 2}},
+	{{2, 0}, {1, 1}, {0, 2}},
 }
 
-func (m Model) renderBoard() string {
-	xStyle := lipgloss.NewStyle().
-		Foreground(styles.Success).
-		Bold(true)
+// winnerOf returns the mark with three in a row, or 0
+func winnerOf(board [3][3]rune) rune {
+	for _, line := range lines {
+		a := board[line[0][1]][line[0][0]]
+		if a != Empty && a == board[line[1][1]][line[1][0]] && a == board[line[2][1]][line[2][0]] {
+			return a
+		}
+	}
+	return 0
+}
 
-	oStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ff6b6b")).
-		Bold(true)
+func boardFull(board [3][3]rune) bool {
+	for _, row := range board {
+		for _, cell := range row {
+			if cell == Empty {
+				return false
+			}
+		}
+	}
+	return true
+}
 
-	var rows []string
+func opponent(mark rune) rune {
+	if mark == 'X' {
+		return 'O'
+	}
+	return 'X'
+}
 
+// BestMove searches the whole game tree with minimax and returns the
+// strongest move for the player to move
+func (g *Game) BestMove() engine.Action {
+	board := g.board
+	mark := g.turn
+	bestX, bestY, bestScore := -1, -1, -2
 	for y := range 3 {
-		var cells []string
 		for x := range 3 {
-			cellContent := " "
-			switch m.board[y][x] {
-			case 'X':
-				cellContent = xStyle.Render("✕")
-			case 'O':
-				cellContent = oStyle.Render("○")
+			if board[y][x] != Empty {
+				continue
 			}
+			board[y][x] = mark
+			score := -minimax(board, opponent(mark))
+			board[y][x] = Empty
+			if score > bestScore {
+				bestX, bestY, bestScore = x, y, score
+			}
+		}
+	}
+	if bestX < 0 {
+		return ""
+	}
+	return Cell(bestX, bestY)
+}
+
+// minimax scores the board from the point of view of the player to move:
+// 1 for a forced win, 0 for a draw and -1 for a forced loss
+func minimax(board [3][3]rune, toMove rune) int {
+	if winner := winnerOf(board); winner != 0 {
+		if winner == toMove {
+			return 1
+		}
+		return -1
+	}
+	if boardFull(board) {
+		return 0
+	}
 
-			style := styles.CellStyle
-			if m.cursorX == x && m.cursorY == y {
-				style = styles.SelectedCellStyle
+	best := -2
+	for y := range 3 {
+		for x := range 3 {
+			if board[y][x] != Empty {
+				continue
 			}
+			board[y][x] = toMove
+			best = max(best, -minimax(board, opponent(toMove)))
+			board[y][x] = Empty
+		}
+	}
+	return best
+}
 
-			cells = append(cells, style.Render(cellContent))
+// savedGame is a game as encoded to JSON
+type savedGame struct {
+	Board [3]string `json:"board"` // rows of "X", "O" and " "
+	Turn  string    `json:"turn"`
+}
+
+func (g *Game) MarshalJSON() ([]byte, error) {
+	state := savedGame{Turn: string(g.turn)}
+	for y, row := range g.board {
+		state.Board[y] = string(row[:])
+	}
+	return json.Marshal(state)
+}
+
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var state savedGame
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	var board [3][3]rune
+	for y, row := range state.Board {
+		cells := []rune(row)
+		if len(cells) != 3 {
+			return fmt.Errorf("row %d is not 3 cells", y+1)
 		}
-		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+		for x, cell := range cells {
+			if cell != 'X' && cell != 'O' && cell != Empty {
+				return fmt.Errorf("unknown mark %q", cell)
+			}
+			board[y][x] = cell
+		}
+	}
+	if state.Turn != "X" && state.Turn != "O" {
+		return fmt.Errorf("unknown turn %q", state.Turn)
 	}
 
-	return strings.Join(rows, "\n")
+	g.board = board
+	g.turn = rune(state.Turn[0])
+	g.winner = winnerOf(board)
+	g.over = g.winner != 0 || boardFull(board)
+	return nil
 }
@@ -0,0 +1,156 @@
+package tictactoe
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+// play makes the moves in order, failing on illegal ones
+func play(t *testing.T, moves ...engine.Action) *Game {
+	t.Helper()
+	g := NewGame()
+	for _, move := range moves {
+		if err := g.Step(move); err != nil {
+			t.Fatalf("Step(%s): %v", move, err)
+		}
+	}
+	return g
+}
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		moves      []engine.Action
+		want       engine.Result
+		winner     rune
+		toMove     int
+		legalMoves int
+	}{
+		{"new game", nil, engine.Result{}, 0, 0, 9},
+		{"X row", []engine.Action{"a1", "a2", "b1", "b2", "c1"},
+			engine.Result{Over: true, Outcome: engine.OutcomeWin, Winner: "X"}, 'X', 1, 0},
+		{"O column", []engine.Action{"a1", "c1", "b1", "c2", "a3", "c3"},
+			engine.Result{Over: true, Outcome: engine.OutcomeLoss, Winner: "O"}, 'O', 0, 0},
+		{"X diagonal", []engine.Action{"a1", "b1", "b2", "c1", "c3"},
+			engine.Result{Over: true, Outcome: engine.OutcomeWin, Winner: "X"}, 'X', 1, 0},
+		{"X anti-diagonal", []engine.Action{"c1", "a1", "b2", "b1", "a3"},
+			engine.Result{Over: true, Outcome: engine.OutcomeWin, Winner: "X"}, 'X', 1, 0},
+		{"draw", []engine.Action{"a1", "b1", "c1", "b2", "a2", "a3", "c2", "c3", "b3"},
+			engine.Result{Over: true, Outcome: engine.OutcomeDraw}, 0, 1, 0},
+		{"win on the last cell", []engine.Action{"a1", "b1", "c1", "a2", "b2", "c2", "b3", "a3", "c3"},
+			engine.Result{Over: true, Outcome: engine.OutcomeWin, Winner: "X"}, 'X', 1, 0},
+		{"under way", []engine.Action{"b2", "a1"}, engine.Result{}, 0, 0, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := play(t, tt.moves...)
+			if got := g.Result(); got != tt.want {
+				t.Errorf("Result() = %+v, want %+v", got, tt.want)
+			}
+			if got := g.Winner(); got != tt.winner {
+				t.Errorf("Winner() = %q, want %q", got, tt.winner)
+			}
+			if got := g.ToMove(); got != tt.toMove {
+				t.Errorf("ToMove() = %d, want %d", got, tt.toMove)
+			}
+			if got := len(g.Legal()); got != tt.legalMoves {
+				t.Errorf("%d legal moves, want %d", got, tt.legalMoves)
+			}
+		})
+	}
+}
+
+func TestIllegalMoves(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []engine.Action
+		move  engine.Action
+	}{
+		{"taken cell", []engine.Action{"b2"}, "b2"},
+		{"column off the board", nil, "d1"},
+		{"row off the board", nil, "a4"},
+		{"row zero", nil, "a0"},
+		{"not a cell", nil, "b"},
+		{"empty", nil, ""},
+		{"after the game", []engine.Action{"a1", "a2", "b1", "b2", "c1"}, "c3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := play(t, tt.moves...)
+			before, _ := json.Marshal(g)
+			err := g.Step(tt.move)
+			if !errors.Is(err, engine.ErrIllegal) {
+				t.Fatalf("Step(%q) = %v, want ErrIllegal", tt.move, err)
+			}
+			if after, _ := json.Marshal(g); string(after) != string(before) {
+				t.Errorf("illegal move changed the game: %s, was %s", after, before)
+			}
+		})
+	}
+}
+
+func TestBestMove(t *testing.T) {
+	tests := []struct {
+		name  string
+		moves []engine.Action
+		want  engine.Action
+	}{
+		{"takes the win", []engine.Action{"a1", "a2", "b1", "b2"}, "c1"},
+		{"blocks", []engine.Action{"a1", "b2", "b1"}, "c1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := play(t, tt.moves...).BestMove(); got != tt.want {
+				t.Errorf("BestMove() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Two perfect players always draw
+func TestBestMoveDraws(t *testing.T) {
+	g := NewGame()
+	for !g.Result().Over {
+		if err := g.Step(g.BestMove()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := g.Result().Outcome; got != engine.OutcomeDraw {
+		t.Errorf("outcome = %v, want a draw", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	g := play(t, "a1", "b2", "c3", "a3")
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewGame()
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	for y := range 3 {
+		for x := range 3 {
+			if restored.At(x, y) != g.At(x, y) {
+				t.Errorf("cell %s = %q, want %q", Cell(x, y), restored.At(x, y), g.At(x, y))
+			}
+		}
+	}
+	if restored.Turn() != 'X' {
+		t.Errorf("Turn() = %q, want X", restored.Turn())
+	}
+
+	for _, bad := range []string{
+		`{"board":["XO","   ","   "],"turn":"X"}`,
+		`{"board":["XQ ","   ","   "],"turn":"X"}`,
+		`{"board":["   ","   ","   "],"turn":"Z"}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), NewGame()); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jakmaz/arcade/internal/games/tictactoe"
+)
+
+// savedState is a game in progress as written to a save file. Who plays
+// O comes from the game's options.
+type savedState struct {
+	Game    *tictactoe.Game `json:"game"`
+	CursorX int             `json:"cursor_x"`
+	CursorY int             `json:"cursor_y"`
+}
+
+func (m *Model) SaveState() ([]byte, error) {
+	return json.Marshal(savedState{
+		Game:    m.game,
+		CursorX: m.cursorX,
+		CursorY: m.cursorY,
+	})
+}
+
+func (m *Model) LoadState(data []byte) error {
+	state := savedState{Game: tictactoe.NewGame()}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid tic-tac-toe save: %w", err)
+	}
+	if state.Game == nil {
+		return fmt.Errorf("invalid tic-tac-toe save: no game")
+	}
+
+	m.game = state.Game
+	m.cursorX = min(max(state.CursorX, 0), 2)
+	m.cursorY = min(max(state.CursorY, 0), 2)
+	return nil
+}
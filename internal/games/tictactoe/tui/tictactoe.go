@@ -0,0 +1,186 @@
+// Package tui plays the tic-tac-toe engine in the terminal
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/games/tictactoe"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+func init() {
+	core.Register(core.GameInfo{
+		ID:          "tictactoe",
+		Name:        "Tic-Tac-Toe",
+		Description: "Classic game of tic-tac-toe",
+		Options: []core.OptionInfo{
+			{Key: "opponent", Values: []string{"computer", "human"}, Default: "computer", Help: "who plays O"},
+		},
+		New: func(options core.Options) core.Game { return New(options) },
+	})
+}
+
+func New(options core.Options) *Model {
+	return &Model{
+		game:     tictactoe.NewGame(),
+		cursorX:  1,
+		cursorY:  1,
+		computer: options.Get("opponent", "computer") == "computer",
+	}
+}
+
+type Model struct {
+	game             *tictactoe.Game
+	cursorX, cursorY int
+	computer         bool // whether the computer plays O
+	paused           bool
+	width, height    int
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyMsg:
+		if m.paused || m.game.Result().Over {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "up":
+			m.cursorY = (m.cursorY + 2) % 3
+		case "down":
+			m.cursorY = (m.cursorY + 1) % 3
+		case "left":
+			m.cursorX = (m.cursorX + 2) % 3
+		case "right":
+			m.cursorX = (m.cursorX + 1) % 3
+		case "enter", " ":
+			if m.place(tictactoe.Cell(m.cursorX, m.cursorY)) && m.computer {
+				m.place(m.game.BestMove())
+			}
+		}
+	}
+	return m, nil
+}
+
+// place plays a move, reporting false when it was not accepted
+func (m *Model) place(action engine.Action) bool {
+	if m.game.Step(action) != nil {
+		return false
+	}
+
+	if m.game.Result().Over && m.computer {
+		name := "perfect_game" // won or drew against perfect play
+		if m.game.Winner() == 'O' {
+			name = "lost_game"
+		}
+		core.Publish(core.Event{Game: "tictactoe", Name: name})
+	}
+	return true
+}
+
+func (m *Model) Pause()  { m.paused = true }
+func (m *Model) Resume() { m.paused = false }
+
+func (m *Model) Engine() engine.Engine {
+	return m.game
+}
+
+func (m *Model) State() core.State {
+	result := m.game.Result()
+	state := core.State{Paused: m.paused, Over: result.Over, Outcome: result.Outcome, Mode: "human"}
+	if m.computer {
+		state.Mode = "computer"
+	}
+	if winner := m.game.Winner(); winner != 0 {
+		state.Winner = m.playerName(winner)
+	}
+	return state
+}
+
+func (m *Model) KeyBindings() []core.KeyBinding {
+	return []core.KeyBinding{
+		{Keys: "↑ ↓ ← →", Help: "move"},
+		{Keys: "Enter", Help: "place"},
+	}
+}
+
+func (m *Model) playerName(mark rune) string {
+	if m.computer {
+		if mark == 'X' {
+			return "You"
+		}
+		return "Computer"
+	}
+	return string(mark)
+}
+
+func (m *Model) View() string {
+	title := styles.TitleStyle.Render("Tic-Tac-Toe")
+
+	board := m.renderBoard()
+
+	var status string
+	switch winner := m.game.Winner(); {
+	case winner != 0:
+		status = styles.SelectedItemStyle.Render("Winner: " + m.playerName(winner))
+	case m.game.Result().Over:
+		status = styles.SelectedItemStyle.Render("Draw!")
+	default:
+		status = styles.SelectedItemStyle.Render("Current Player: " + string(m.game.Turn()))
+	}
+
+	help := styles.HelpStyle.Render(core.HelpLine(m.KeyBindings()))
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		board,
+		"",
+		status,
+		"",
+		help,
+	)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m *Model) renderBoard() string {
+	xStyle := styles.Player1Style
+	oStyle := styles.Player2Style
+
+	var rows []string
+
+	for y := range 3 {
+		var cells []string
+		for x := range 3 {
+			cellContent := " "
+			switch m.game.At(x, y) {
+			case 'X':
+				cellContent = xStyle.Render("✕")
+			case 'O':
+				cellContent = oStyle.Render("○")
+			}
+
+			style := styles.CellStyle
+			if m.cursorX == x && m.cursorY == y {
+				style = styles.SelectedCellStyle
+			}
+
+			cells = append(cells, style.Render(cellContent))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+
+	return strings.Join(rows, "\n")
+}
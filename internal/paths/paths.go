@@ -0,0 +1,37 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigDir returns the arcade configuration directory.
+// It honours $XDG_CONFIG_HOME and falls back to ~/.config/arcade.
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "arcade"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".config", "arcade"), nil
+}
+
+// DataDir returns the directory arcade keeps its records in.
+// It honours $XDG_DATA_HOME and falls back to ~/.local/share/arcade.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "arcade"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".local", "share", "arcade"), nil
+}
@@ -0,0 +1,162 @@
+// Package replay records games as the inputs that drove them, so they
+// can be played back exactly. Games are deterministic given their seed,
+// the keys pressed, or actions played by bots, and the number of ticks
+// between them.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/paths"
+	"github.com/jakmaz/arcade/internal/storage"
+)
+
+// fileVersion is bumped whenever the replay layout changes incompatibly
+const fileVersion = 1
+
+// Replay is a recorded game
+type Replay struct {
+	Version  int             `json:"version"`
+	Game     string          `json:"game"`
+	Options  core.Options    `json:"options"`         // including the seed
+	State    json.RawMessage `json:"state,omitempty"` // the saved game the recording starts from, if resumed
+	Recorded time.Time       `json:"recorded"`
+	Ticks    int             `json:"ticks"` // ticks the game received in total
+	Events   []Event         `json:"events"`
+}
+
+// Event is a key press that reached the game, or an action a bot played
+// on the game's engine
+type Event struct {
+	Tick   int           `json:"tick"`             // ticks the game had received before the event
+	Key    string        `json:"key,omitempty"`    // as returned by tea.KeyMsg.String
+	Action engine.Action `json:"action,omitempty"` // played on the engine instead of a key
+}
+
+// New starts a recording of a game created with options
+func New(gameID string, options core.Options) *Replay {
+	return &Replay{
+		Version: fileVersion,
+		Game:    gameID,
+		Options: options,
+	}
+}
+
+// Tick records a tick delivered to the game
+func (r *Replay) Tick() {
+	r.Ticks++
+}
+
+// Key records a key press delivered to the game
+func (r *Replay) Key(msg tea.KeyMsg) {
+	r.Events = append(r.Events, Event{Tick: r.Ticks, Key: msg.String()})
+}
+
+// Action records an action played on the game's engine
+func (r *Replay) Action(action engine.Action) {
+	r.Events = append(r.Events, Event{Tick: r.Ticks, Action: action})
+}
+
+// Dir returns the directory replays are kept in
+func Dir() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "replays"), nil
+}
+
+// Write stores the replay in the replay directory and returns its path
+func Write(r *Replay) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	r.Recorded = time.Now()
+
+	// Games can finish within the same second, e.g. in a tournament or in
+	// two terminals, so claim a name of our own before writing it
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	f, err := os.CreateTemp(dir, fmt.Sprintf("%s-%s-*.json", r.Game, r.Recorded.Format("20060102-150405")))
+	if err != nil {
+		return "", fmt.Errorf("failed to create replay file: %w", err)
+	}
+	f.Close()
+
+	if err := WriteFile(f.Name(), r); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// WriteFile stores the replay at path
+func WriteFile(path string, r *Replay) error {
+	if r.Recorded.IsZero() {
+		r.Recorded = time.Now()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode replay: %w", err)
+	}
+	return storage.WriteFile(path, data)
+}
+
+// Read loads a replay file
+func Read(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %w", err)
+	}
+
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if r.Version != fileVersion {
+		return nil, fmt.Errorf("%s was recorded by an incompatible version of arcade", path)
+	}
+	if r.Game == "" {
+		return nil, errors.New("replay does not name a game")
+	}
+	return &r, nil
+}
+
+// keyTypes maps key names back to the key types that produce them
+var keyTypes = map[string]tea.KeyType{}
+
+func init() {
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if name := t.String(); name != "" {
+			if _, exists := keyTypes[name]; !exists {
+				keyTypes[name] = t
+			}
+		}
+	}
+}
+
+// KeyMsg turns a recorded key back into the message that produced it
+func KeyMsg(key string) tea.KeyMsg {
+	alt := false
+	if rest, ok := strings.CutPrefix(key, "alt+"); ok && rest != "" {
+		alt = true
+		key = rest
+	}
+
+	if t, ok := keyTypes[key]; ok {
+		return tea.KeyMsg{Type: t, Alt: alt}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: alt}
+}
@@ -0,0 +1,141 @@
+// Package saves keeps games that were left halfway through, one save
+// per game, so they can be resumed later.
+package saves
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/paths"
+	"github.com/jakmaz/arcade/internal/storage"
+)
+
+// fileVersion is bumped whenever the save layout, or the state of any
+// game, changes incompatibly
+const fileVersion = 1
+
+// ErrNoSave is returned when a game has no save to resume
+var ErrNoSave = errors.New("no saved game")
+
+// File is a saved game
+type File struct {
+	Version int             `json:"version"`
+	Game    string          `json:"game"`
+	Options core.Options    `json:"options,omitempty"`
+	Saved   time.Time       `json:"saved"`
+	Elapsed time.Duration   `json:"elapsed"` // play time before the save
+	State   json.RawMessage `json:"state"`   // encoded by the game
+}
+
+// Dir returns the directory saves are kept in
+func Dir() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "saves"), nil
+}
+
+func path(gameID string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, gameID+".json"), nil
+}
+
+// Write saves a game, replacing its previous save
+func Write(save File) error {
+	p, err := path(save.Game)
+	if err != nil {
+		return err
+	}
+
+	save.Version = fileVersion
+	data, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode save: %w", err)
+	}
+	return storage.WriteFile(p, data)
+}
+
+// Read loads the save of a game, or returns ErrNoSave
+func Read(gameID string) (File, error) {
+	p, err := path(gameID)
+	if err != nil {
+		return File{}, err
+	}
+	return read(p)
+}
+
+func read(p string) (File, error) {
+	var save File
+
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return save, fmt.Errorf("%w at %s", ErrNoSave, p)
+	}
+	if err != nil {
+		return save, fmt.Errorf("failed to read save: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &save); err != nil {
+		return save, fmt.Errorf("failed to parse %s: %w", p, err)
+	}
+	if save.Version != fileVersion {
+		return save, fmt.Errorf("%s was saved by an incompatible version of arcade", p)
+	}
+	return save, nil
+}
+
+// Delete removes the save of a game, if there is one
+func Delete(gameID string) error {
+	p, err := path(gameID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete save: %w", err)
+	}
+	return nil
+}
+
+// List returns every readable save, most recent first
+func List() ([]File, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saves: %w", err)
+	}
+
+	var list []File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		save, err := read(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		list = append(list, save)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Saved.After(list[j].Saved)
+	})
+	return list, nil
+}
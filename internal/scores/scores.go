@@ -0,0 +1,276 @@
+// Package scores keeps the history of finished games on disk, shared by
+// every arcade process of the user.
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/config"
+	"github.com/jakmaz/arcade/internal/paths"
+	"github.com/jakmaz/arcade/internal/storage"
+)
+
+// fileVersion is bumped whenever the file layout changes incompatibly
+const fileVersion = 1
+
+// Record is one finished game
+type Record struct {
+	Game     string            `json:"game"`
+	Mode     string            `json:"mode,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+	Score    int               `json:"score"`
+	Outcome  string            `json:"outcome,omitempty"` // win, loss or draw for the first player, empty for solo games
+	Details  map[string]int    `json:"details,omitempty"`
+	Duration time.Duration     `json:"duration"`
+	Date     time.Time         `json:"date"`
+	Player   string            `json:"player"`
+}
+
+type file struct {
+	Version int      `json:"version"`
+	Records []Record `json:"records"`
+}
+
+// Path returns the location of the score file
+func Path() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "scores.json"), nil
+}
+
+// Load reads every recorded game, oldest first. A missing file means no
+// games have been played yet.
+func Load() ([]Record, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return load(path)
+}
+
+// Add appends a finished game to the score file
+func Add(record Record) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := storage.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Read again under the lock so records added by other processes
+	// since we last looked are kept
+	records, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	return save(path, append(records, record))
+}
+
+func load(path string) ([]Record, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scores: %w", err)
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if f.Version > fileVersion {
+		return nil, fmt.Errorf("%s was written by a newer version of arcade", path)
+	}
+
+	return f.Records, nil
+}
+
+func save(path string, records []Record) error {
+	data, err := json.MarshalIndent(file{Version: fileVersion, Records: records}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scores: %w", err)
+	}
+	return storage.WriteFile(path, data)
+}
+
+// Player returns the name finished games are recorded under: the one
+// set in the config file, or the user's login name
+func Player() string {
+	if name := config.Get().Player; name != "" {
+		return name
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "player"
+}
+
+// Top returns the n highest scores of a game mode, best first. Ties go
+// to the earlier game.
+func Top(records []Record, game, mode string, n int) []Record {
+	var top []Record
+	for _, r := range records {
+		if r.Game == game && r.Mode == mode {
+			top = append(top, r)
+		}
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Score > top[j].Score
+	})
+
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// Standing is a player's results in a game with winners and losers
+type Standing struct {
+	Player              string
+	Wins, Losses, Draws int
+}
+
+// Standings ranks the players of a game mode by wins, then by fewest
+// losses, and returns the first n
+func Standings(records []Record, game, mode string, n int) []Standing {
+	byPlayer := make(map[string]*Standing)
+	var standings []*Standing
+	for _, r := range records {
+		if r.Game != game || r.Mode != mode || r.Outcome == "" {
+			continue
+		}
+
+		s, ok := byPlayer[r.Player]
+		if !ok {
+			s = &Standing{Player: r.Player}
+			byPlayer[r.Player] = s
+			standings = append(standings, s)
+		}
+
+		switch r.Outcome {
+		case "win":
+			s.Wins++
+		case "loss":
+			s.Losses++
+		case "draw":
+			s.Draws++
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Wins != standings[j].Wins {
+			return standings[i].Wins > standings[j].Wins
+		}
+		return standings[i].Losses < standings[j].Losses
+	})
+
+	var top []Standing
+	for _, s := range standings {
+		if len(top) == n {
+			break
+		}
+		top = append(top, *s)
+	}
+	return top
+}
+
+// Filter selects recorded games. Empty fields match everything.
+type Filter struct {
+	Game   string
+	Mode   string
+	Player string
+	Since  time.Time // inclusive
+	Until  time.Time // exclusive
+}
+
+// Match reports whether a record passes the filter
+func (f Filter) Match(r Record) bool {
+	switch {
+	case f.Game != "" && r.Game != f.Game:
+		return false
+	case f.Mode != "" && r.Mode != f.Mode:
+		return false
+	case f.Player != "" && r.Player != f.Player:
+		return false
+	case !f.Since.IsZero() && r.Date.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !r.Date.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Leaderboards filters the records and ranks them per game and mode,
+// keeping the best n of each. n of 0 keeps them all. Solo games rank by
+// score; games with a winner, whose score is always 0, rank wins first,
+// then draws, then losses, quickest first.
+func Leaderboards(records []Record, filter Filter, n int) []Record {
+	var matched []Record
+	for _, r := range records {
+		if filter.Match(r) {
+			matched = append(matched, r)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.Game != b.Game {
+			return a.Game < b.Game
+		}
+		if a.Mode != b.Mode {
+			return a.Mode < b.Mode
+		}
+		return ranksAbove(a, b)
+	})
+
+	if n == 0 {
+		return matched
+	}
+
+	var ranked []Record
+	count := 0
+	for i, r := range matched {
+		if i > 0 && (r.Game != matched[i-1].Game || r.Mode != matched[i-1].Mode) {
+			count = 0
+		}
+		if count < n {
+			ranked = append(ranked, r)
+		}
+		count++
+	}
+	return ranked
+}
+
+// outcomeRanks orders the outcomes of games with a winner, best first
+var outcomeRanks = map[string]int{"win": 0, "draw": 1, "loss": 2}
+
+// ranksAbove reports whether a ranks above b, both of the same game and
+// mode
+func ranksAbove(a, b Record) bool {
+	if a.Outcome == "" && b.Outcome == "" {
+		return a.Score > b.Score
+	}
+	if outcomeRanks[a.Outcome] != outcomeRanks[b.Outcome] {
+		return outcomeRanks[a.Outcome] < outcomeRanks[b.Outcome]
+	}
+	return a.Duration < b.Duration
+}
@@ -0,0 +1,36 @@
+package scores
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLeaderboards(t *testing.T) {
+	records := []Record{
+		{Game: "tictactoe", Player: "loss", Outcome: "loss", Duration: time.Second},
+		{Game: "snake", Player: "low", Score: 10},
+		{Game: "tictactoe", Player: "slow win", Outcome: "win", Duration: time.Minute},
+		{Game: "tictactoe", Player: "draw", Outcome: "draw", Duration: time.Second},
+		{Game: "snake", Player: "high", Score: 30},
+		{Game: "tictactoe", Player: "quick win", Outcome: "win", Duration: 10 * time.Second},
+		{Game: "snake", Player: "middle", Score: 20},
+	}
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{0, []string{"high", "middle", "low", "quick win", "slow win", "draw", "loss"}},
+		{2, []string{"high", "middle", "quick win", "slow win"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range Leaderboards(records, Filter{}, tt.n) {
+			got = append(got, r.Player)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("top %d: %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
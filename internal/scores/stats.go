@@ -0,0 +1,112 @@
+package scores
+
+import (
+	"fmt"
+	"time"
+)
+
+// recentScores is how many of the latest scores a summary keeps
+const recentScores = 20
+
+// Summary totals a game's recorded history
+type Summary struct {
+	Game     string
+	Played   int
+	PlayTime time.Duration
+
+	// Games with a winner
+	Wins, Losses, Draws int
+
+	// Games that only keep a score
+	Scored  int
+	Best    int
+	Average float64
+	Recent  []int // the latest scores, oldest first
+
+	// LinesPerMinute is the average clearing speed of games that record
+	// "lines", zero for the others
+	LinesPerMinute float64
+}
+
+// Summarize totals the records of one game
+func Summarize(records []Record, game string) Summary {
+	summary := Summary{Game: game}
+
+	total := 0
+	lines := 0
+	var linesTime time.Duration
+	for _, r := range records {
+		if r.Game != game {
+			continue
+		}
+		summary.Played++
+		summary.PlayTime += r.Duration
+
+		switch r.Outcome {
+		case "win":
+			summary.Wins++
+		case "loss":
+			summary.Losses++
+		case "draw":
+			summary.Draws++
+		default:
+			if summary.Scored == 0 || r.Score > summary.Best {
+				summary.Best = r.Score
+			}
+			summary.Scored++
+			total += r.Score
+			summary.Recent = append(summary.Recent, r.Score)
+		}
+
+		if n, ok := r.Details["lines"]; ok {
+			lines += n
+			linesTime += r.Duration
+		}
+	}
+
+	if summary.Scored > 0 {
+		summary.Average = float64(total) / float64(summary.Scored)
+	}
+	if len(summary.Recent) > recentScores {
+		summary.Recent = summary.Recent[len(summary.Recent)-recentScores:]
+	}
+	if linesTime > 0 {
+		summary.LinesPerMinute = float64(lines) / linesTime.Minutes()
+	}
+	return summary
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws values as a row of bars scaled between the lowest and
+// highest value
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min(low, v)
+		high = max(high, v)
+	}
+
+	line := make([]rune, len(values))
+	for i, v := range values {
+		level := len(sparkBlocks) - 1
+		if high > low {
+			level = (v - low) * (len(sparkBlocks) - 1) / (high - low)
+		}
+		line[i] = sparkBlocks[level]
+	}
+	return string(line)
+}
+
+// FormatDuration prints a play time as m:ss, or h:mm:ss for long ones
+func FormatDuration(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
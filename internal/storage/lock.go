@@ -0,0 +1,104 @@
+// Package storage writes the files arcade keeps in its data directory
+// safely, when several arcade processes may be running at once.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockRetry = 10 * time.Millisecond
+	// lockStale is how old a lock file has to be before it is assumed to
+	// belong to a process that died while holding it. Locks are held for
+	// a single small write, so a few seconds is plenty.
+	lockStale = 3 * time.Second
+	// lockTimeout outlasts lockStale, so a lock left behind by a crash is
+	// waited out and taken over instead of failing the write
+	lockTimeout = lockStale + 2*time.Second
+)
+
+// Lock takes an exclusive lock next to path, so that only one arcade
+// process rewrites the file at a time. It returns the function that
+// releases the lock.
+//
+// The lock is a file created with O_EXCL, which works the same on every
+// platform and filesystem arcade runs on. It holds a token of its own,
+// so releasing it never removes a lock another process has taken over.
+// Missing directories are created.
+func Lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	token := newToken()
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = f.WriteString(token)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("failed to write lock file: %w", err)
+			}
+			return func() { unlock(lockPath, token) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		if breakStale(lockPath) {
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// newToken identifies one holder of a lock
+func newToken() string {
+	return fmt.Sprintf("%d-%016x", os.Getpid(), rand.Uint64())
+}
+
+// unlock removes the lock file if it is still the one holding token
+func unlock(lockPath, token string) {
+	if data, err := os.ReadFile(lockPath); err == nil && string(data) == token {
+		os.Remove(lockPath)
+	}
+}
+
+// breakStale removes the lock file at lockPath when it is stale, and
+// reports whether it did. The lock is first renamed to a name of its own:
+// when several processes find the same stale lock only one of them gets
+// to rename it, and one that renamed a fresh lock taken in the meantime
+// puts it back.
+func breakStale(lockPath string) bool {
+	if info, err := os.Stat(lockPath); err != nil || time.Since(info.ModTime()) <= lockStale {
+		return false
+	}
+
+	aside := lockPath + "." + newToken()
+	if err := os.Rename(lockPath, aside); err != nil {
+		return false
+	}
+	defer os.Remove(aside)
+
+	if info, err := os.Stat(aside); err == nil && time.Since(info.ModTime()) <= lockStale {
+		// Link rather than rename, which would replace a lock taken since
+		os.Link(aside, lockPath)
+		return false
+	}
+	return true
+}
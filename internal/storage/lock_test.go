@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockExcludes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := Lock(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer unlock()
+
+			data, _ := os.ReadFile(path)
+			n, _ := strconv.Atoi(string(data))
+			if err := WriteFile(path, []byte(strconv.Itoa(n+1))); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if data, _ := os.ReadFile(path); string(data) != "20" {
+		t.Errorf("counter = %s, want 20", data)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestLockTakesOverStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	lockPath := path + ".lock"
+	if err := os.WriteFile(lockPath, []byte("1-dead"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStale)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Everyone waiting finds the same stale lock, and still only one
+	// holds the lock at a time
+	var mu sync.Mutex
+	holders := 0
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := Lock(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			holders++
+			if holders > 1 {
+				t.Error("lock held twice")
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+
+	matches, _ := filepath.Glob(lockPath + "*")
+	if len(matches) != 0 {
+		t.Errorf("lock files left behind: %v", matches)
+	}
+}
+
+func TestUnlockKeepsOtherLocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.json")
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process took the lock over, thinking it stale
+	if err := os.WriteFile(path+".lock", []byte("2-other"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if data, err := os.ReadFile(path + ".lock"); err != nil || string(data) != "2-other" {
+		t.Errorf("unlock removed another process's lock: %q, %v", data, err)
+	}
+}
@@ -0,0 +1,261 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	colorful "github.com/lucasb-eyer/go-colorful"
+)
+
+// Contrast ratios WCAG 2.1 asks for: text at level AA, and graphics such
+// as board borders and game pieces
+const (
+	MinTextContrast    = 4.5
+	MinGraphicContrast = 3.0
+)
+
+// MinColorDistance is the CIEDE2000 distance below which two colors of a
+// game, such as two tetris pieces, are hard to tell apart at a glance
+const MinColorDistance = 10.0
+
+// ChessDarkSquare is the background of the dark squares of the chess board
+var ChessDarkSquare = lipgloss.Color("#2a2a2a")
+
+// DefaultCheckBackground is the background colors are checked against when
+// a theme leaves the background to the terminal
+const DefaultCheckBackground = "#000000"
+
+// Vision is a way of seeing colors
+type Vision int
+
+const (
+	NormalVision Vision = iota
+	Protanopia          // no red cones
+	Deuteranopia        // no green cones
+	Tritanopia          // no blue cones
+)
+
+// Visions lists every way of seeing colors CheckTheme simulates
+var Visions = []Vision{NormalVision, Protanopia, Deuteranopia, Tritanopia}
+
+func (v Vision) String() string {
+	switch v {
+	case Protanopia:
+		return "protanopia"
+	case Deuteranopia:
+		return "deuteranopia"
+	case Tritanopia:
+		return "tritanopia"
+	}
+	return "normal vision"
+}
+
+// visionMatrices simulate color vision deficiencies in linear RGB, from
+// Machado, Oliveira and Fernandes (2009) at full severity
+var visionMatrices = map[Vision][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// Simulate returns the color as someone with the vision sees it
+func Simulate(c colorful.Color, v Vision) colorful.Color {
+	m, ok := visionMatrices[v]
+	if !ok {
+		return c
+	}
+	r, g, b := c.LinearRgb()
+	return colorful.LinearRgb(
+		m[0][0]*r+m[0][1]*g+m[0][2]*b,
+		m[1][0]*r+m[1][1]*g+m[1][2]*b,
+		m[2][0]*r+m[2][1]*g+m[2][2]*b,
+	).Clamped()
+}
+
+// Luminance returns the WCAG relative luminance of a color, from 0 for
+// black to 1 for white
+func Luminance(c colorful.Color) float64 {
+	r, g, b := c.LinearRgb()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// ContrastRatio returns the WCAG contrast ratio of two colors, from 1 for
+// the same luminance to 21 for black on white
+func ContrastRatio(a, b colorful.Color) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	return (math.Max(la, lb) + 0.05) / (math.Min(la, lb) + 0.05)
+}
+
+// Contrast is how well a color stands out from a background
+type Contrast struct {
+	Role       string // key of the foreground role
+	Against    string // what the background is
+	Foreground colorful.Color
+	Background colorful.Color
+	Ratio      float64
+	Min        float64
+}
+
+// OK reports whether the contrast is enough
+func (c Contrast) OK() bool { return c.Ratio >= c.Min }
+
+// Confusion is two colors of a game that look alike
+type Confusion struct {
+	Vision   Vision
+	A, B     string // keys of the roles
+	Distance float64
+}
+
+// Check is what CheckTheme found
+type Check struct {
+	Background colorful.Color
+	Contrasts  []Contrast
+	Confusions []Confusion
+	Skipped    []string // roles without a hex color, which can't be checked
+}
+
+// Failures counts the contrasts that aren't enough and the confusions
+func (c *Check) Failures() int {
+	failures := len(c.Confusions)
+	for _, contrast := range c.Contrasts {
+		if !contrast.OK() {
+			failures++
+		}
+	}
+	return failures
+}
+
+// Roles checked for contrast, and the colors of a game that must look
+// different from each other
+var (
+	textRoles = []string{
+		"ui.primary", "ui.secondary", "ui.accent", "ui.success", "ui.warning", "ui.error",
+	}
+	graphicRoles = []string{
+		"board.border", "board.cellBorder", "board.selectedCell",
+		"games.tictactoe.player1", "games.tictactoe.player2",
+		"games.snake.body", "games.snake.head", "games.snake.food",
+		"games.chess.whitePieces", "games.chess.blackPieces",
+		"games.tetris.iPiece", "games.tetris.oPiece", "games.tetris.tPiece", "games.tetris.sPiece",
+		"games.tetris.zPiece", "games.tetris.jPiece", "games.tetris.lPiece",
+	}
+	chessRoles     = []string{"games.chess.whitePieces", "games.chess.blackPieces"}
+	playerRoles    = []string{"games.tictactoe.player1", "games.tictactoe.player2"}
+	tetrisRoles    = graphicRoles[len(graphicRoles)-7:]
+	distinctGroups = [][]string{tetrisRoles, playerRoles}
+)
+
+// CheckTheme checks the contrast of a theme's colors with its background,
+// and that the tetris pieces and the two players look different with
+// normal vision and with each color vision deficiency. background is used
+// when the theme leaves the background to the terminal, and may be empty
+// for DefaultCheckBackground.
+func CheckTheme(t Theme, background string) (*Check, error) {
+	if bg := ColorString(t.TerminalBackground()); t.ShouldUseTerminalBackground() && IsHexColor(bg) {
+		background = bg
+	}
+	if background == "" {
+		background = DefaultCheckBackground
+	}
+	bg, err := colorful.Hex(background)
+	if err != nil || !IsHexColor(background) {
+		return nil, fmt.Errorf("invalid background %q, expected a hex color such as \"#282a36\"", background)
+	}
+
+	check := &Check{Background: bg}
+	dark := isDark(bg)
+	colors := map[string]colorful.Color{}
+	for _, role := range Roles {
+		if c, ok := hexColor(role.Color(t), dark); ok {
+			colors[role.Key] = c
+		} else if role.Key != TerminalBackgroundKey && !strings.HasPrefix(role.Key, "board.") {
+			check.Skipped = append(check.Skipped, role.Key)
+		}
+	}
+
+	contrast := func(key, against string, background colorful.Color, min float64) {
+		if fg, ok := colors[key]; ok {
+			check.Contrasts = append(check.Contrasts, Contrast{
+				Role: key, Against: against,
+				Foreground: fg, Background: background,
+				Ratio: ContrastRatio(fg, background), Min: min,
+			})
+		}
+	}
+	for _, key := range textRoles {
+		contrast(key, "background", bg, MinTextContrast)
+	}
+	// Pieces also stand on the chess board's dark squares and on the
+	// background of tictactoe cells
+	darkSquare, _ := colorful.Hex(string(ChessDarkSquare))
+	cell, hasCell := colors["board.cellBackground"]
+	for _, key := range graphicRoles {
+		contrast(key, "background", bg, MinGraphicContrast)
+		if slices.Contains(chessRoles, key) {
+			contrast(key, "chess dark squares", darkSquare, MinGraphicContrast)
+		}
+		if slices.Contains(playerRoles, key) && hasCell {
+			contrast(key, "board.cellBackground", cell, MinGraphicContrast)
+		}
+	}
+
+	for _, vision := range Visions {
+		for _, group := range distinctGroups {
+			for i, a := range group {
+				for _, b := range group[i+1:] {
+					ca, okA := colors[a]
+					cb, okB := colors[b]
+					if !okA || !okB {
+						continue
+					}
+					distance := Simulate(ca, vision).DistanceCIEDE2000(Simulate(cb, vision)) * 100
+					if distance < MinColorDistance {
+						check.Confusions = append(check.Confusions, Confusion{vision, a, b, distance})
+					}
+				}
+			}
+		}
+	}
+	return check, nil
+}
+
+// isDark reports whether a background is dark, for colors that adapt to
+// it
+func isDark(bg colorful.Color) bool {
+	return Luminance(bg) < 0.18
+}
+
+// hexColor returns a color given in hex, choosing the variant of an
+// adaptive color for a dark or light background
+func hexColor(c lipgloss.TerminalColor, dark bool) (colorful.Color, bool) {
+	var s string
+	switch c := c.(type) {
+	case lipgloss.Color:
+		s = string(c)
+	case lipgloss.AdaptiveColor:
+		s = c.Light
+		if dark {
+			s = c.Dark
+		}
+	}
+	if !IsHexColor(s) {
+		return colorful.Color{}, false
+	}
+	color, err := colorful.Hex(s)
+	return color, err == nil
+}
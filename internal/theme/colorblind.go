@@ -0,0 +1,57 @@
+package theme
+
+import "github.com/charmbracelet/lipgloss"
+
+// colorblindPalette colors the games for players with a color vision
+// deficiency. Each one passes CheckTheme: the tetris pieces and the two
+// sides stay apart with protanopia, deuteranopia and tritanopia, and keep
+// their contrast with the backgrounds it's meant for.
+type colorblindPalette struct {
+	tetris           [7]string // I, O, T, S, Z, J, L
+	player1, player2 string
+}
+
+var (
+	// Okabe and Ito's palette, with a lighter blue to stand out from dark
+	// backgrounds
+	colorblindDark = colorblindPalette{
+		tetris:  [7]string{"#56b4e9", "#f0e442", "#cc79a7", "#009e73", "#d55e00", "#5b7cff", "#e69f00"},
+		player1: "#56b4e9",
+		player2: "#e69f00",
+	}
+	colorblindLight = colorblindPalette{
+		tetris:  [7]string{"#3399bb", "#777700", "#661188", "#227766", "#880000", "#4455ff", "#bb8866"},
+		player1: "#2266ff",
+		player2: "#cc7700",
+	}
+)
+
+// Colorblind returns a theme with t's colors, except for game colors that
+// are told apart by hue: the tetris pieces, the players and the chess
+// sides take a palette that works with any color vision deficiency. A
+// theme with its own background gets the palette for it; one that leaves
+// the background to the terminal adapts to the terminal's.
+func Colorblind(t Theme) Theme {
+	cb := *baseOf(t)
+
+	color := func(pick func(colorblindPalette) string) lipgloss.TerminalColor {
+		dark, light := pick(colorblindDark), pick(colorblindLight)
+		if bg, ok := hexColor(t.TerminalBackground(), true); ok && t.ShouldUseTerminalBackground() {
+			if isDark(bg) {
+				return lipgloss.Color(dark)
+			}
+			return lipgloss.Color(light)
+		}
+		return lipgloss.AdaptiveColor{Light: light, Dark: dark}
+	}
+	tetris := func(i int) lipgloss.TerminalColor {
+		return color(func(p colorblindPalette) string { return p.tetris[i] })
+	}
+
+	cb.tetrisI, cb.tetrisO, cb.tetrisT, cb.tetrisS = tetris(0), tetris(1), tetris(2), tetris(3)
+	cb.tetrisZ, cb.tetrisJ, cb.tetrisL = tetris(4), tetris(5), tetris(6)
+	cb.player1 = color(func(p colorblindPalette) string { return p.player1 })
+	cb.player2 = color(func(p colorblindPalette) string { return p.player2 })
+	cb.whitePiece, cb.blackPiece = cb.player1, cb.player2
+	return &cb
+}
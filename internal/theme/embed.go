@@ -0,0 +1,18 @@
+package theme
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed themes/*.yaml
+var embeddedThemes embed.FS
+
+// builtinThemes loads the themes shipped with arcade
+func builtinThemes() ([]themeFile, []error) {
+	dir, err := fs.Sub(embeddedThemes, "themes")
+	if err != nil {
+		return nil, []error{err}
+	}
+	return loadThemesFromFS(dir, "built-in themes")
+}
@@ -0,0 +1,466 @@
+package theme
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Color scheme formats Import reads
+const (
+	FormatBase16          = "base16"
+	FormatAlacritty       = "alacritty"
+	FormatKitty           = "kitty"
+	FormatWindowsTerminal = "wt"
+)
+
+// ImportFormats lists the color scheme formats Import reads
+var ImportFormats = []string{FormatBase16, FormatAlacritty, FormatKitty, FormatWindowsTerminal}
+
+// ansiNames are the palette names of the 16 terminal colors, in order
+var ansiNames = [16]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"brightBlack", "brightRed", "brightGreen", "brightYellow", "brightBlue", "brightMagenta", "brightCyan", "brightWhite",
+}
+
+// colorScheme is a terminal color scheme, reduced to the colors themes use
+type colorScheme struct {
+	name       string
+	background string
+	foreground string
+	ansi       [16]string // the terminal colors, see ansiNames, empty if not set
+	orange     string     // base16 has one, terminals don't
+}
+
+// Import converts a terminal color scheme, read from file, to a theme.
+// scheme picks one of several schemes in a Windows Terminal settings file.
+// The theme is named after the scheme, if the format names it.
+func Import(file string, data []byte, format, scheme string) (*ThemeDefinition, error) {
+	var s *colorScheme
+	var err error
+	switch format {
+	case FormatBase16:
+		s, err = importBase16(data)
+	case FormatAlacritty:
+		s, err = importAlacritty(file, data)
+	case FormatKitty:
+		s, err = importKitty(data)
+	case FormatWindowsTerminal:
+		s, err = importWindowsTerminal(data, scheme)
+	default:
+		return nil, fmt.Errorf("unknown color scheme format '%s' (expected one of %s)", format, strings.Join(ImportFormats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.definition()
+}
+
+// definition maps the scheme's colors to theme roles, naming them in the
+// palette so they're easy to adjust
+func (s *colorScheme) definition() (*ThemeDefinition, error) {
+	palette := map[string]string{}
+	add := func(name, color string) error {
+		if color == "" {
+			return nil
+		}
+		hex, ok := normalizeHex(color)
+		if !ok {
+			return fmt.Errorf("invalid %s color %q", name, color)
+		}
+		palette[name] = hex
+		return nil
+	}
+
+	for _, required := range []struct{ name, color string }{{"bg", s.background}, {"fg", s.foreground}} {
+		if required.color == "" {
+			return nil, fmt.Errorf("color scheme has no %s color", required.name)
+		}
+		if err := add(required.name, required.color); err != nil {
+			return nil, err
+		}
+	}
+	for i, color := range s.ansi {
+		if i < 8 && color == "" {
+			return nil, fmt.Errorf("color scheme has no %s color", ansiNames[i])
+		}
+		if err := add(ansiNames[i], color); err != nil {
+			return nil, err
+		}
+	}
+	if err := add("orange", s.orange); err != nil {
+		return nil, err
+	}
+
+	// pick returns the first of the colors the scheme has
+	pick := func(names ...string) string {
+		for _, name := range names {
+			if _, ok := palette[name]; ok {
+				return name
+			}
+		}
+		return names[len(names)-1]
+	}
+	muted := pick("brightBlack", "white")
+
+	return &ThemeDefinition{
+		Name:    themeName(s.name),
+		Palette: palette,
+		UI: UIColors{
+			Primary:   "fg",
+			Secondary: muted,
+			Accent:    "blue",
+			Success:   "green",
+			Warning:   "yellow",
+			Error:     "red",
+		},
+		Board: BoardColors{
+			Border:         muted,
+			Background:     "none",
+			CellBorder:     muted,
+			CellBackground: "none",
+			SelectedCell:   "blue",
+		},
+		Games: GameColors{
+			Chess: ChessColors{
+				WhitePieces: pick("brightWhite", "fg"),
+				BlackPieces: muted,
+			},
+			Snake: SnakeColors{
+				Body: "green",
+				Head: "blue",
+				Food: "red",
+			},
+			Tetris: TetrisColors{
+				IPiece: "cyan",
+				OPiece: "yellow",
+				TPiece: "magenta",
+				SPiece: "green",
+				ZPiece: "red",
+				JPiece: "blue",
+				LPiece: pick("orange", "brightRed", "red"),
+			},
+			Tictactoe: TicTacToeColors{
+				Player1: "green",
+				Player2: "red",
+			},
+		},
+	}, nil
+}
+
+// importBase16 reads a base16 scheme, in either the original layout with
+// "scheme" and the colors at the top, or the newer one with a "palette"
+func importBase16(data []byte) (*colorScheme, error) {
+	settings, err := yamlSettings(data)
+	if err != nil {
+		return nil, err
+	}
+	color := func(base string) string {
+		if c, ok := settings["palette."+base]; ok {
+			return c
+		}
+		return settings[base]
+	}
+	name := settings["scheme"]
+	if name == "" {
+		name = settings["name"]
+	}
+
+	// The mapping of base16-shell
+	s := &colorScheme{
+		name:       name,
+		background: color("base00"),
+		foreground: color("base05"),
+		orange:     color("base09"),
+	}
+	normal := []string{"base00", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base05"}
+	bright := []string{"base03", "base08", "base0B", "base0A", "base0D", "base0E", "base0C", "base07"}
+	for i := range normal {
+		s.ansi[i] = color(normal[i])
+		s.ansi[i+8] = color(bright[i])
+	}
+	return s, nil
+}
+
+// importAlacritty reads the colors of an Alacritty config, in TOML or, for
+// .yml and .yaml files, the older YAML
+func importAlacritty(file string, data []byte) (*colorScheme, error) {
+	read := tomlSettings
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yml", ".yaml":
+		read = yamlSettings
+	}
+	settings, err := read(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &colorScheme{
+		background: settings["colors.primary.background"],
+		foreground: settings["colors.primary.foreground"],
+	}
+	for i, name := range ansiNames[:8] {
+		s.ansi[i] = settings["colors.normal."+name]
+		s.ansi[i+8] = settings["colors.bright."+name]
+	}
+	return s, nil
+}
+
+// importKitty reads a kitty theme or config. kitty themes name themselves
+// in a "## name:" comment.
+func importKitty(data []byte) (*colorScheme, error) {
+	s := &colorScheme{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if name, ok := strings.CutPrefix(line, "## name:"); ok {
+			s.name = strings.TrimSpace(name)
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+		switch key {
+		case "background":
+			s.background = value
+		case "foreground":
+			s.foreground = value
+		default:
+			if n, ok := strings.CutPrefix(key, "color"); ok {
+				if i, err := strconv.Atoi(n); err == nil && i >= 0 && i < 16 {
+					s.ansi[i] = value
+				}
+			}
+		}
+	}
+	return s, scanner.Err()
+}
+
+// wtScheme is a Windows Terminal color scheme
+type wtScheme struct {
+	Name         string `json:"name"`
+	Background   string `json:"background"`
+	Foreground   string `json:"foreground"`
+	Black        string `json:"black"`
+	Red          string `json:"red"`
+	Green        string `json:"green"`
+	Yellow       string `json:"yellow"`
+	Blue         string `json:"blue"`
+	Purple       string `json:"purple"`
+	Cyan         string `json:"cyan"`
+	White        string `json:"white"`
+	BrightBlack  string `json:"brightBlack"`
+	BrightRed    string `json:"brightRed"`
+	BrightGreen  string `json:"brightGreen"`
+	BrightYellow string `json:"brightYellow"`
+	BrightBlue   string `json:"brightBlue"`
+	BrightPurple string `json:"brightPurple"`
+	BrightCyan   string `json:"brightCyan"`
+	BrightWhite  string `json:"brightWhite"`
+}
+
+// importWindowsTerminal reads a Windows Terminal color scheme, or the
+// scheme called name from the "schemes" of a settings file
+func importWindowsTerminal(data []byte, name string) (*colorScheme, error) {
+	var file struct {
+		wtScheme
+		Schemes []wtScheme `json:"schemes"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse Windows Terminal scheme: %w", err)
+	}
+
+	wt := file.wtScheme
+	if len(file.Schemes) > 0 {
+		var names []string
+		found := false
+		for _, scheme := range file.Schemes {
+			names = append(names, scheme.Name)
+			if scheme.Name == name || (name == "" && len(file.Schemes) == 1) {
+				wt, found = scheme, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("choose one of the schemes: %s", strings.Join(names, ", "))
+		}
+	}
+
+	return &colorScheme{
+		name:       wt.Name,
+		background: wt.Background,
+		foreground: wt.Foreground,
+		ansi: [16]string{
+			wt.Black, wt.Red, wt.Green, wt.Yellow, wt.Blue, wt.Purple, wt.Cyan, wt.White,
+			wt.BrightBlack, wt.BrightRed, wt.BrightGreen, wt.BrightYellow, wt.BrightBlue, wt.BrightPurple, wt.BrightCyan, wt.BrightWhite,
+		},
+	}, nil
+}
+
+// yamlSettings returns the scalars of a YAML file by their dotted path
+func yamlSettings(data []byte) (map[string]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse color scheme: %w", err)
+	}
+	settings := map[string]string{}
+	var walk func(node *yaml.Node, prefix string)
+	walk = func(node *yaml.Node, prefix string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, prefix)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], prefix+node.Content[i].Value+".")
+			}
+		case yaml.ScalarNode:
+			settings[strings.TrimSuffix(prefix, ".")] = node.Value
+		}
+	}
+	walk(&doc, "")
+	return settings, nil
+}
+
+// tomlSettings returns the strings and other plain values of a TOML file
+// by their dotted path. It reads the part of TOML color schemes use:
+// tables, key = value pairs and inline tables. Arrays, such as the key
+// bindings of a full config, are skipped; multi-line strings are not read.
+func tomlSettings(data []byte) (map[string]string, error) {
+	settings := map[string]string{}
+	table := ""
+	depth := 0 // of the brackets of an array spanning lines
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if depth > 0 {
+			depth += tomlBrackets(line)
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			name := strings.Trim(line, "[]")
+			table = tomlKey(name) + "."
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("failed to parse color scheme: line %d: expected key = value", lineNumber)
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "[") {
+			depth = tomlBrackets(value)
+			continue
+		}
+		setTOMLValue(settings, table+tomlKey(key), value)
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("failed to parse color scheme: unterminated array")
+	}
+	return settings, scanner.Err()
+}
+
+// tomlBrackets counts the brackets line opens less the ones it closes,
+// leaving brackets in strings alone
+func tomlBrackets(line string) int {
+	depth := 0
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		}
+	}
+	return depth
+}
+
+func setTOMLValue(settings map[string]string, key, value string) {
+	if inline, ok := strings.CutPrefix(value, "{"); ok {
+		for _, pair := range strings.Split(strings.TrimSuffix(inline, "}"), ",") {
+			if k, v, ok := strings.Cut(pair, "="); ok {
+				setTOMLValue(settings, key+"."+tomlKey(k), strings.TrimSpace(v))
+			}
+		}
+		return
+	}
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	settings[key] = value
+}
+
+// tomlKey turns a possibly quoted, dotted TOML key into a dotted path
+func tomlKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// stripTOMLComment removes a trailing comment, leaving '#' in strings
+// alone
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// normalizeHex turns the ways schemes write colors, "#RRGGBB", "0xRRGGBB"
+// or "RRGGBB", into "#rrggbb"
+func normalizeHex(color string) (string, bool) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	color = strings.TrimPrefix(color, "0x")
+	if !strings.HasPrefix(color, "#") {
+		color = "#" + color
+	}
+	return color, IsHexColor(color)
+}
+
+// themeName makes a scheme name into a theme name such as "solarized-dark"
+func themeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '_' || r == '.':
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,105 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+// alacrittyConfig is a full Alacritty config, with its colors among
+// everything else
+const alacrittyConfig = `import = [
+  "~/.config/alacritty/fonts.toml", # shared with the laptop
+]
+
+[window]
+padding = { x = 4, y = 4 }
+opacity = 0.95
+
+[font]
+size = 12.0
+
+[colors.primary]
+background = "#282a36"
+foreground = "#f8f8f2"
+
+[colors.normal]
+black = "#21222c"
+red = "#ff5555"
+green = "#50fa7b"
+yellow = "#f1fa8c"
+blue = "#bd93f9"
+magenta = "#ff79c6"
+cyan = "#8be9fd"
+white = "#f8f8f2"
+
+[colors.bright]
+black = "#6272a4"
+red = "#ff6e6e"
+green = "#69ff94"
+yellow = "#ffffa5"
+blue = "#d6acff"
+magenta = "#ff92df"
+cyan = "#a4ffff"
+white = "#ffffff"
+
+[keyboard]
+bindings = [
+  { key = "V", mods = "Control|Shift", action = "Paste" },
+  { key = "N", mods = "Command", action = "CreateNewWindow" },
+  { key = "Key1", mods = "Alt", chars = "[1]" },
+]
+
+[[hints.enabled]]
+regex = "(https?://)[^\u0000-\u001F\u007F-\u009F<>\"\\s{-}\\^⟨⟩‘]+"
+`
+
+const alacrittyYAML = `colors:
+  primary:
+    background: '0x282a36'
+    foreground: '0xf8f8f2'
+  normal:
+    black: '0x21222c'
+    red: '0xff5555'
+    green: '0x50fa7b'
+    yellow: '0xf1fa8c'
+    blue: '0xbd93f9'
+    magenta: '0xff79c6'
+    cyan: '0x8be9fd'
+    white: '0xf8f8f2'
+key_bindings:
+  - { key: V, mods: Control|Shift, action: Paste }
+`
+
+func TestImportAlacritty(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		wantErr string
+	}{
+		{name: "config with key bindings", file: "alacritty.toml", data: alacrittyConfig},
+		{name: "older YAML", file: "alacritty.yml", data: alacrittyYAML},
+		// Not read as YAML, which would only complain about missing colors
+		{name: "broken TOML", file: "alacritty.toml", data: "[colors.primary]\nbackground\n", wantErr: "line 2: expected key = value"},
+		{name: "unterminated array", file: "alacritty.toml", data: "import = [\n  \"fonts.toml\",\n", wantErr: "unterminated array"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, err := Import(tt.file, []byte(tt.data), FormatAlacritty, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range map[string]string{"bg": "#282a36", "fg": "#f8f8f2", "red": "#ff5555", "white": "#f8f8f2"} {
+				if got := def.Palette[name]; got != want {
+					t.Errorf("palette %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package theme
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"gopkg.in/yaml.v3"
@@ -11,92 +17,185 @@ import (
 
 // ThemeDefinition represents the YAML structure for theme files
 type ThemeDefinition struct {
-	Name    string
-	Palette map[string]string
-	UI      UIColors
-	Board   BoardColors
-	Games   GameColors
+	Name    string            `yaml:"name,omitempty"`
+	Extends string            `yaml:"extends,omitempty"` // theme whose settings this one starts from
+	Palette map[string]string `yaml:"palette,omitempty"`
+	UI      UIColors          `yaml:"ui,omitempty"`
+	Board   BoardColors       `yaml:"board,omitempty"`
+	Games   GameColors        `yaml:"games,omitempty"`
 }
 
 type UIColors struct {
-	Primary   string
-	Secondary string
-	Accent    string
-	Success   string
-	Warning   string
-	Error     string
+	Primary   string `yaml:"primary,omitempty"`
+	Secondary string `yaml:"secondary,omitempty"`
+	Accent    string `yaml:"accent,omitempty"`
+	Success   string `yaml:"success,omitempty"`
+	Warning   string `yaml:"warning,omitempty"`
+	Error     string `yaml:"error,omitempty"`
 }
 
 type BoardColors struct {
-	Border         string
-	Background     string
-	CellBorder     string
-	CellBackground string
-	SelectedCell   string
+	Border         string `yaml:"border,omitempty"`
+	Background     string `yaml:"background,omitempty"`
+	CellBorder     string `yaml:"cellBorder,omitempty"`
+	CellBackground string `yaml:"cellBackground,omitempty"`
+	SelectedCell   string `yaml:"selectedCell,omitempty"`
 }
 
 type GameColors struct {
-	Chess     ChessColors
-	Snake     SnakeColors
-	Tetris    TetrisColors
-	Tictactoe TicTacToeColors
+	Chess     ChessColors     `yaml:"chess,omitempty"`
+	Snake     SnakeColors     `yaml:"snake,omitempty"`
+	Tetris    TetrisColors    `yaml:"tetris,omitempty"`
+	Tictactoe TicTacToeColors `yaml:"tictactoe,omitempty"`
 }
 
 type ChessColors struct {
-	WhitePieces string
-	BlackPieces string
+	WhitePieces string `yaml:"whitePieces,omitempty"`
+	BlackPieces string `yaml:"blackPieces,omitempty"`
 }
 
 type SnakeColors struct {
-	Body string
-	Head string
-	Food string
+	Body string `yaml:"body,omitempty"`
+	Head string `yaml:"head,omitempty"`
+	Food string `yaml:"food,omitempty"`
 }
 
 type TetrisColors struct {
-	IPiece string
-	OPiece string
-	TPiece string
-	SPiece string
-	ZPiece string
-	JPiece string
-	LPiece string
+	IPiece string `yaml:"iPiece,omitempty"`
+	OPiece string `yaml:"oPiece,omitempty"`
+	TPiece string `yaml:"tPiece,omitempty"`
+	SPiece string `yaml:"sPiece,omitempty"`
+	ZPiece string `yaml:"zPiece,omitempty"`
+	JPiece string `yaml:"jPiece,omitempty"`
+	LPiece string `yaml:"lPiece,omitempty"`
 }
 
 type TicTacToeColors struct {
-	Player1 string
-	Player2 string
+	Player1 string `yaml:"player1,omitempty"`
+	Player2 string `yaml:"player2,omitempty"`
 }
 
-// LoadThemeFromFile loads a theme from a YAML file
+// LoadThemeFromFile loads a theme from a YAML file. A theme it extends
+// must already be registered.
 func LoadThemeFromFile(path string) (Theme, error) {
+	def, err := readThemeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	files, errs := globalManager.resolveThemes([]themeFile{{def: def, path: path}})
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return files[0].theme, nil
+}
+
+func readThemeFile(path string) (*ThemeDefinition, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read theme file %s: %w", path, err)
 	}
+	return parseTheme(data, path)
+}
 
+func parseTheme(data []byte, path string) (*ThemeDefinition, error) {
 	var def ThemeDefinition
 	if err := yaml.Unmarshal(data, &def); err != nil {
 		return nil, fmt.Errorf("failed to parse theme file %s: %w", path, err)
 	}
+	return &def, nil
+}
+
+// EncodeTheme writes a theme definition as a theme file, with comment,
+// if any, at the top
+func EncodeTheme(def *ThemeDefinition, comment string) ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(def); err != nil {
+		return nil, fmt.Errorf("failed to encode theme %s: %w", def.Name, err)
+	}
+	doc.HeadComment = comment
+	formatThemeNode(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode theme %s: %w", def.Name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatThemeNode writes colors the way the built-in themes do: hex
+// colors in double quotes, and the palette starting with the background,
+// foreground and terminal colors
+func formatThemeNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && strings.HasPrefix(node.Value, "#") {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		formatThemeNode(child)
+	}
 
-	return createThemeFromDefinition(&def)
+	if palette := lookup(node, "palette"); node.Kind == yaml.MappingNode && palette != nil {
+		rank := func(name string) int {
+			if i := slices.Index(append([]string{"bg", "fg"}, ansiNames[:]...), name); i >= 0 {
+				return i
+			}
+			return len(ansiNames) + 2
+		}
+		pairs := make([][2]*yaml.Node, 0, len(palette.Content)/2)
+		for i := 0; i+1 < len(palette.Content); i += 2 {
+			pairs = append(pairs, [2]*yaml.Node{palette.Content[i], palette.Content[i+1]})
+		}
+		slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+			return rank(a[0].Value) - rank(b[0].Value)
+		})
+		palette.Content = palette.Content[:0]
+		for _, pair := range pairs {
+			palette.Content = append(palette.Content, pair[0], pair[1])
+		}
+	}
 }
 
-// LoadThemesFromDirectories loads themes from user directories in the correct override order.
-// The hierarchy is (from lowest to highest priority):
-// 1. Built-in themes (embedded)
-// 2. USER_CONFIG/opencode/themes/*.yaml
+// LoadThemesFromDirectory loads every *.yaml theme in dir. A missing
+// directory has no themes. Themes may extend each other or a registered
+// theme. Files that can't be loaded are left out and reported together.
 func LoadThemesFromDirectory(dir string) ([]Theme, error) {
-	var themes []Theme
+	files, errs := loadThemeDirectory(dir)
+	files, resolveErrs := globalManager.resolveThemes(files)
+	errs = append(errs, resolveErrs...)
 
+	themes := make([]Theme, len(files))
+	for i, file := range files {
+		themes[i] = file.theme
+	}
+	return themes, errors.Join(errs...)
+}
+
+// themeFile is a theme definition, the file it was loaded from and, once
+// resolved, the theme it describes
+type themeFile struct {
+	def   *ThemeDefinition
+	path  string
+	theme Theme
+}
+
+func loadThemeDirectory(dir string) ([]themeFile, []error) {
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return themes, nil // Directory doesn't exist, return empty slice
+		return nil, nil
 	}
+	return loadThemesFromFS(os.DirFS(dir), dir)
+}
+
+// loadThemesFromFS parses every *.yaml theme at the root of fsys. dir
+// names the location in messages. A file that can't be parsed doesn't
+// stop the others from loading.
+func loadThemesFromFS(fsys fs.FS, dir string) ([]themeFile, []error) {
+	var themes []themeFile
+	var errs []error
 
-	entries, err := os.ReadDir(dir)
+	entries, err := fs.ReadDir(fsys, ".")
 	if err != nil {
-		return nil, fmt.Errorf("failed to read themes directory %s: %w", dir, err)
+		return nil, []error{fmt.Errorf("failed to read themes directory %s: %w", dir, err)}
 	}
 
 	for _, entry := range entries {
@@ -105,22 +204,65 @@ func LoadThemesFromDirectory(dir string) ([]Theme, error) {
 		}
 
 		themePath := filepath.Join(dir, entry.Name())
-		theme, err := LoadThemeFromFile(themePath)
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read theme file %s: %w", themePath, err))
+			continue
+		}
+		def, err := parseTheme(data, themePath)
 		if err != nil {
-			// Log error but continue loading other themes
-			fmt.Fprintf(os.Stderr, "Warning: failed to load theme %s: %v\n", themePath, err)
+			errs = append(errs, err)
 			continue
 		}
 
-		themes = append(themes, theme)
+		themes = append(themes, themeFile{def: def, path: themePath})
 	}
 
-	return themes, nil
+	return themes, errs
 }
 
-// createThemeFromDefinition creates a BaseTheme from a ThemeDefinition
-func createThemeFromDefinition(def *ThemeDefinition) (*BaseTheme, error) {
-	theme := &BaseTheme{name: def.Name}
+// inherit returns def with the settings it leaves empty taken from
+// parent. Palette entries are merged, def's replacing parent's, so colors
+// parent gives by palette name follow def's palette.
+func inherit(def, parent *ThemeDefinition) *ThemeDefinition {
+	merged := *def
+	merged.Extends = ""
+	mergeSettings(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(parent).Elem())
+	return &merged
+}
+
+func mergeSettings(dst, src reflect.Value) {
+	for i := range dst.NumField() {
+		field, parent := dst.Field(i), src.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			mergeSettings(field, parent)
+		case reflect.String:
+			if field.String() == "" {
+				field.Set(parent)
+			}
+		case reflect.Map:
+			merged := reflect.MakeMap(field.Type())
+			for _, m := range []reflect.Value{parent, field} {
+				for _, key := range m.MapKeys() {
+					merged.SetMapIndex(key, m.MapIndex(key))
+				}
+			}
+			field.Set(merged)
+		}
+	}
+}
+
+// createThemeFromDefinition creates a BaseTheme from a ThemeDefinition.
+// Colors def leaves out come from base, or defaults when base is nil.
+func createThemeFromDefinition(def *ThemeDefinition, base *BaseTheme) (*BaseTheme, error) {
+	theme := &BaseTheme{}
+	if base != nil {
+		*theme = *base
+	}
+	theme.name = def.Name
+	theme.def = def
+	theme.base = base
 
 	// Helper function to resolve color references and create lipgloss.TerminalColor
 	resolveColor := func(colorStr string) lipgloss.TerminalColor {
@@ -141,156 +283,156 @@ func createThemeFromDefinition(def *ThemeDefinition) (*BaseTheme, error) {
 	// UI Colors with fallbacks
 	if def.UI.Primary != "" {
 		theme.primary = resolveColor(def.UI.Primary)
-	} else {
+	} else if base == nil {
 		theme.primary = lipgloss.Color("#ffffff")
 	}
 
 	if def.UI.Secondary != "" {
 		theme.secondary = resolveColor(def.UI.Secondary)
-	} else {
+	} else if base == nil {
 		theme.secondary = lipgloss.Color("#888888")
 	}
 
 	if def.UI.Accent != "" {
 		theme.accent = resolveColor(def.UI.Accent)
-	} else {
+	} else if base == nil {
 		theme.accent = lipgloss.Color("#0066cc")
 	}
 
 	if def.UI.Success != "" {
 		theme.success = resolveColor(def.UI.Success)
-	} else {
+	} else if base == nil {
 		theme.success = lipgloss.Color("#22c55e")
 	}
 
 	if def.UI.Warning != "" {
 		theme.warning = resolveColor(def.UI.Warning)
-	} else {
+	} else if base == nil {
 		theme.warning = lipgloss.Color("#f59e0b")
 	}
 
 	if def.UI.Error != "" {
 		theme.error = resolveColor(def.UI.Error)
-	} else {
+	} else if base == nil {
 		theme.error = lipgloss.Color("#ef4444")
 	}
 
 	// Board Colors with fallbacks
 	if def.Board.Border != "" {
 		theme.boardBorder = resolveColor(def.Board.Border)
-	} else {
+	} else if base == nil {
 		theme.boardBorder = theme.secondary
 	}
 
 	if def.Board.Background != "" {
 		theme.boardBackground = resolveColor(def.Board.Background)
-	} else {
+	} else if base == nil {
 		theme.boardBackground = lipgloss.Color("")
 	}
 
 	if def.Board.CellBorder != "" {
 		theme.cellBorder = resolveColor(def.Board.CellBorder)
-	} else {
+	} else if base == nil {
 		theme.cellBorder = theme.secondary
 	}
 
 	if def.Board.CellBackground != "" {
 		theme.cellBackground = resolveColor(def.Board.CellBackground)
-	} else {
+	} else if base == nil {
 		theme.cellBackground = lipgloss.Color("")
 	}
 
 	if def.Board.SelectedCell != "" {
 		theme.selectedCell = resolveColor(def.Board.SelectedCell)
-	} else {
+	} else if base == nil {
 		theme.selectedCell = theme.accent
 	}
 
 	// Game Colors - TicTacToe
 	if def.Games.Tictactoe.Player1 != "" {
 		theme.player1 = resolveColor(def.Games.Tictactoe.Player1)
-	} else {
+	} else if base == nil {
 		theme.player1 = lipgloss.Color("#22c55e")
 	}
 
 	if def.Games.Tictactoe.Player2 != "" {
 		theme.player2 = resolveColor(def.Games.Tictactoe.Player2)
-	} else {
+	} else if base == nil {
 		theme.player2 = lipgloss.Color("#ef4444")
 	}
 
 	// Game Colors - Snake
 	if def.Games.Snake.Body != "" {
 		theme.snakeBody = resolveColor(def.Games.Snake.Body)
-	} else {
+	} else if base == nil {
 		theme.snakeBody = theme.success
 	}
 
 	if def.Games.Snake.Head != "" {
 		theme.snakeHead = resolveColor(def.Games.Snake.Head)
-	} else {
+	} else if base == nil {
 		theme.snakeHead = theme.accent
 	}
 
 	if def.Games.Snake.Food != "" {
 		theme.food = resolveColor(def.Games.Snake.Food)
-	} else {
+	} else if base == nil {
 		theme.food = lipgloss.Color("#ef4444")
 	}
 
 	// Game Colors - Chess
 	if def.Games.Chess.WhitePieces != "" {
 		theme.whitePiece = resolveColor(def.Games.Chess.WhitePieces)
-	} else {
+	} else if base == nil {
 		theme.whitePiece = lipgloss.Color("#ffffff")
 	}
 
 	if def.Games.Chess.BlackPieces != "" {
 		theme.blackPiece = resolveColor(def.Games.Chess.BlackPieces)
-	} else {
+	} else if base == nil {
 		theme.blackPiece = lipgloss.Color("#444444")
 	}
 
 	// Game Colors - Tetris
 	if def.Games.Tetris.IPiece != "" {
 		theme.tetrisI = resolveColor(def.Games.Tetris.IPiece)
-	} else {
+	} else if base == nil {
 		theme.tetrisI = lipgloss.Color("#00f5ff")
 	}
 
 	if def.Games.Tetris.OPiece != "" {
 		theme.tetrisO = resolveColor(def.Games.Tetris.OPiece)
-	} else {
+	} else if base == nil {
 		theme.tetrisO = lipgloss.Color("#ffff00")
 	}
 
 	if def.Games.Tetris.TPiece != "" {
 		theme.tetrisT = resolveColor(def.Games.Tetris.TPiece)
-	} else {
+	} else if base == nil {
 		theme.tetrisT = lipgloss.Color("#800080")
 	}
 
 	if def.Games.Tetris.SPiece != "" {
 		theme.tetrisS = resolveColor(def.Games.Tetris.SPiece)
-	} else {
+	} else if base == nil {
 		theme.tetrisS = lipgloss.Color("#00ff00")
 	}
 
 	if def.Games.Tetris.ZPiece != "" {
 		theme.tetrisZ = resolveColor(def.Games.Tetris.ZPiece)
-	} else {
+	} else if base == nil {
 		theme.tetrisZ = lipgloss.Color("#ff0000")
 	}
 
 	if def.Games.Tetris.JPiece != "" {
 		theme.tetrisJ = resolveColor(def.Games.Tetris.JPiece)
-	} else {
+	} else if base == nil {
 		theme.tetrisJ = lipgloss.Color("#0000ff")
 	}
 
 	if def.Games.Tetris.LPiece != "" {
 		theme.tetrisL = resolveColor(def.Games.Tetris.LPiece)
-	} else {
+	} else if base == nil {
 		theme.tetrisL = lipgloss.Color("#ffa500")
 	}
 
@@ -298,7 +440,7 @@ func createThemeFromDefinition(def *ThemeDefinition) (*BaseTheme, error) {
 	if bgColor, exists := def.Palette["bg"]; exists {
 		theme.terminalBackground = resolveColor(bgColor)
 		theme.useTerminalBackground = true
-	} else {
+	} else if base == nil {
 		theme.terminalBackground = lipgloss.Color("")
 		theme.useTerminalBackground = false
 	}
@@ -1,22 +1,47 @@
 package theme
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
+
+	"github.com/jakmaz/arcade/internal/paths"
+	"github.com/jakmaz/arcade/internal/storage"
 )
 
 // Manager handles theme registration and switching
 type Manager struct {
 	mu           sync.RWMutex
 	themes       map[string]Theme
+	sources      map[string][]string // where each theme was defined, last one winning
 	currentTheme Theme
 	defaultTheme Theme
+	initialized  bool
+	warnings     []error // themes that failed to load
 }
 
 var globalManager = &Manager{
-	themes: make(map[string]Theme),
+	themes:  make(map[string]Theme),
+	sources: make(map[string][]string),
+}
+
+// BuiltinSource is the source of themes that come with arcade
+const BuiltinSource = "built-in"
+
+// ThemePathEnv lists extra theme files or directories, separated like
+// $PATH, with later entries overriding earlier ones
+const ThemePathEnv = "ARCADE_THEME_PATH"
+
+// ThemeSource describes where a theme was loaded from
+type ThemeSource struct {
+	Name      string
+	Source    string   // BuiltinSource or the theme's file
+	Overrides []string // sources of themes of the same name it replaced
 }
 
 // GetManager returns the global theme manager
@@ -26,10 +51,22 @@ func GetManager() *Manager {
 
 // RegisterTheme adds a theme to the registry
 func (m *Manager) RegisterTheme(theme Theme) {
+	m.registerFrom(theme, BuiltinSource)
+}
+
+// registerFrom adds a theme loaded from source, replacing any theme of
+// the same name
+func (m *Manager) registerFrom(theme Theme, source string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	m.themes[theme.Name()] = theme
+	m.sources[theme.Name()] = append(m.sources[theme.Name()], source)
+
+	// Keep the current theme pointing at the theme registered under its name
+	if m.currentTheme != nil && m.currentTheme.Name() == theme.Name() {
+		m.currentTheme = theme
+	}
 
 	// Set as default if it's the first theme registered
 	if m.defaultTheme == nil {
@@ -91,47 +128,324 @@ func (m *Manager) ListThemes() []string {
 	return names
 }
 
-// LoadThemesFromDirectories loads themes from multiple directories
+// Sources describes where every registered theme came from, sorted by
+// name
+func (m *Manager) Sources() []ThemeSource {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sources []ThemeSource
+	for name, list := range m.sources {
+		last := len(list) - 1
+		sources = append(sources, ThemeSource{Name: name, Source: list[last], Overrides: list[:last]})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+	return sources
+}
+
+// LoadThemesFromDirectories loads themes from multiple directories,
+// later ones overriding earlier ones. Themes that can't be loaded are
+// left out and reported together.
 func (m *Manager) LoadThemesFromDirectories(dirs ...string) error {
+	var errs []error
 	for _, dir := range dirs {
-		themes, err := LoadThemesFromDirectory(dir)
+		errs = append(errs, m.loadThemesFrom(dir)...)
+	}
+	return errors.Join(errs...)
+}
+
+// loadThemesFrom registers the theme in a file, or the themes in a
+// directory
+func (m *Manager) loadThemesFrom(path string) []error {
+	var files []themeFile
+	var errs []error
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		def, err := readThemeFile(path)
 		if err != nil {
-			return fmt.Errorf("failed to load themes from %s: %w", dir, err)
+			return []error{err}
 		}
+		files = []themeFile{{def: def, path: path}}
+	} else {
+		files, errs = loadThemeDirectory(path)
+	}
 
-		for _, theme := range themes {
-			m.RegisterTheme(theme)
+	files, resolveErrs := m.resolveThemes(files)
+	for _, file := range files {
+		m.registerFrom(file.theme, file.path)
+	}
+	return append(errs, resolveErrs...)
+}
+
+// resolveThemes builds the themes defined in files. A theme may extend
+// another one in files or a registered theme; one extending its own name
+// extends the registered theme it replaces. Themes that can't be built
+// are left out and reported.
+func (m *Manager) resolveThemes(files []themeFile) ([]themeFile, []error) {
+	r := m.newResolver(files)
+
+	var resolved []themeFile
+	var errs []error
+	for i := range files {
+		theme, err := r.resolve(&files[i], nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", files[i].path, err))
+			continue
 		}
+		file := files[i]
+		file.theme = theme
+		resolved = append(resolved, file)
 	}
+	return resolved, errs
+}
 
-	return nil
+// themeResolver builds themes, following what they extend
+type themeResolver struct {
+	manager *Manager
+	byName  map[string]*themeFile
+	built   map[*themeFile]*BaseTheme
+}
+
+// newResolver builds the themes in files, which may extend each other or
+// the manager's themes
+func (m *Manager) newResolver(files []themeFile) *themeResolver {
+	r := &themeResolver{
+		manager: m,
+		byName:  make(map[string]*themeFile),
+		built:   make(map[*themeFile]*BaseTheme),
+	}
+	for i := range files {
+		r.byName[files[i].def.Name] = &files[i]
+	}
+	return r
+}
+
+// ExtendsError is a theme whose extends can't be followed: the themes
+// extend each other, or one of them extends a theme that doesn't exist
+type ExtendsError struct {
+	Extends string   // what the theme extends
+	Cycle   []string // the themes extending each other, back to the first
+	Missing string   // the theme that doesn't exist, when there's no cycle
+}
+
+func (e *ExtendsError) Error() string {
+	if len(e.Cycle) > 0 {
+		return fmt.Sprintf("extends %q: cycle %s", e.Extends, strings.Join(e.Cycle, " → "))
+	}
+	return fmt.Sprintf("extends %q: parent %q not found", e.Extends, e.Missing)
+}
+
+// resolve builds the theme in file. chain lists the themes extending it,
+// to catch cycles.
+func (r *themeResolver) resolve(file *themeFile, chain []string) (*BaseTheme, error) {
+	if theme, ok := r.built[file]; ok {
+		return theme, nil
+	}
+
+	def := file.def
+	chain = append(chain, def.Name)
+
+	var base *BaseTheme
+	if def.Extends != "" {
+		parent, err := r.parent(def, chain)
+		if err != nil {
+			return nil, err
+		}
+		// Inherit settings rather than colors, so palette changes apply
+		// to the parent's colors too
+		if parent.def != nil {
+			def = inherit(def, parent.def)
+			base = parent.base
+		} else {
+			base = parent
+		}
+	}
+
+	theme, err := createThemeFromDefinition(def, base)
+	if err != nil {
+		return nil, err
+	}
+	r.built[file] = theme
+	return theme, nil
+}
+
+func (r *themeResolver) parent(def *ThemeDefinition, chain []string) (*BaseTheme, error) {
+	if file, ok := r.byName[def.Extends]; ok && def.Extends != def.Name {
+		if i := slices.Index(chain, def.Extends); i >= 0 {
+			cycle := append(slices.Clone(chain[i:]), def.Extends)
+			return nil, &ExtendsError{Extends: def.Extends, Cycle: cycle}
+		}
+		parent, err := r.resolve(file, chain)
+		// Report the problem as one with what this theme extends
+		var extendsErr *ExtendsError
+		if errors.As(err, &extendsErr) {
+			return nil, &ExtendsError{Extends: def.Extends, Cycle: extendsErr.Cycle, Missing: extendsErr.Missing}
+		}
+		return parent, err
+	}
+	if parent, ok := r.manager.GetTheme(def.Extends); ok {
+		return baseOf(parent), nil
+	}
+	return nil, &ExtendsError{Extends: def.Extends, Missing: def.Extends}
 }
 
-// Initialize loads themes from standard locations
+// UserThemeDir returns the themes directory in the config directory,
+// where new themes are saved
+func UserThemeDir() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// ErrThemeExists is returned when saving a theme over an existing file
+var ErrThemeExists = errors.New("theme file already exists")
+
+// SaveTheme writes a theme to the user themes directory, named after the
+// theme, with comment at the top. It doesn't replace an existing file
+// unless overwrite is set.
+func SaveTheme(def *ThemeDefinition, comment string, overwrite bool) (string, error) {
+	data, err := EncodeTheme(def, comment)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := UserThemeDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, def.Name+".yaml")
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return path, fmt.Errorf("%s: %w", path, ErrThemeExists)
+	}
+	if err := storage.WriteFile(path, data); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// UserThemeDirs returns the places user themes are loaded from, lowest
+// priority first: the themes directory in the config directory, then the
+// entries of $ARCADE_THEME_PATH
+func UserThemeDirs() []string {
+	var dirs []string
+	if dir, err := UserThemeDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	for _, entry := range filepath.SplitList(os.Getenv(ThemePathEnv)) {
+		if entry != "" {
+			dirs = append(dirs, entry)
+		}
+	}
+	return dirs
+}
+
+// UserThemeFiles returns the theme files found in UserThemeDirs
+func UserThemeFiles() []string {
+	var files []string
+	for _, path := range UserThemeDirs() {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(path, "*.yaml"))
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// Initialize loads themes from standard locations, once. Themes loaded
+// later override earlier ones of the same name:
+// 1. default and system
+// 2. the built-in themes embedded in the binary
+// 3. the user's themes, see UserThemeDirs
+//
+// Themes that can't be loaded are skipped, see Warnings.
 func (m *Manager) Initialize() error {
-	// Register built-in themes first
+	m.mu.Lock()
+	if m.initialized {
+		m.mu.Unlock()
+		return nil
+	}
+	m.initialized = true
+	m.mu.Unlock()
+
+	warnings := m.load()
+	m.mu.Lock()
+	m.warnings = warnings
+	m.mu.Unlock()
+	return nil
+}
+
+// Warnings reports the themes that failed to load, the last time themes
+// were loaded from the standard locations
+func (m *Manager) Warnings() []error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.warnings)
+}
+
+// load registers the themes from standard locations, see Initialize. A
+// broken theme doesn't keep the others from loading.
+func (m *Manager) load() []error {
+	errs := m.loadBuiltin()
+	for _, dir := range UserThemeDirs() {
+		errs = append(errs, m.loadThemesFrom(dir)...)
+	}
+	return errs
+}
+
+// loadBuiltin registers the themes that come with arcade
+func (m *Manager) loadBuiltin() []error {
 	m.RegisterTheme(NewDefaultTheme())
 	m.RegisterTheme(NewSystemTheme())
 
-	// Get executable directory for built-in themes
-	execPath, err := getCurrentExecutableDir()
-	if err == nil {
-		builtinDir := filepath.Join(execPath, "themes")
-		m.LoadThemesFromDirectories(builtinDir)
+	themes, errs := builtinThemes()
+	themes, resolveErrs := m.resolveThemes(themes)
+	errs = append(errs, resolveErrs...)
+	for _, file := range themes {
+		m.RegisterTheme(file.theme)
 	}
+	return errs
+}
 
-	// Load built-in themes from the source code location (for development)
-	m.LoadThemesFromDirectories("internal/theme/themes")
+// Reload loads the themes from standard locations again, picking up
+// changes to the user's themes, and reports the themes that couldn't be
+// loaded. The current theme stays selected, by name, while it exists; if
+// it failed to load, its last version is kept.
+func (m *Manager) Reload() []error {
+	fresh := &Manager{
+		themes:  make(map[string]Theme),
+		sources: make(map[string][]string),
+	}
+	errs := fresh.load()
 
-	// If no themes were loaded, we already have default theme registered
-	return nil
-}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-// getCurrentExecutableDir returns the directory containing the current executable
-func getCurrentExecutableDir() (string, error) {
-	// For now, just return an error since we don't need this for development
-	// This would be implemented for production builds
-	return "", fmt.Errorf("executable directory detection not implemented")
+	current := fresh.currentTheme
+	if m.currentTheme != nil {
+		name := m.currentTheme.Name()
+		if theme, exists := fresh.themes[name]; exists {
+			current = theme
+		} else if len(errs) > 0 {
+			fresh.themes[name] = m.currentTheme
+			fresh.sources[name] = m.sources[name]
+			current = m.currentTheme
+		}
+	}
+	m.themes = fresh.themes
+	m.sources = fresh.sources
+	m.defaultTheme = fresh.defaultTheme
+	m.currentTheme = current
+	m.initialized = true
+	m.warnings = errs
+	return errs
 }
 
 // Convenience functions for the global manager
@@ -156,6 +470,11 @@ func GetTheme(name string) (Theme, bool) {
 	return globalManager.GetTheme(name)
 }
 
+// Sources describes where the global manager's themes came from
+func Sources() []ThemeSource {
+	return globalManager.Sources()
+}
+
 // ListThemes returns all registered theme names from the global manager
 func ListThemes() []string {
 	return globalManager.ListThemes()
@@ -165,3 +484,13 @@ func ListThemes() []string {
 func Initialize() error {
 	return globalManager.Initialize()
 }
+
+// Warnings reports the global manager's themes that failed to load
+func Warnings() []error {
+	return globalManager.Warnings()
+}
+
+// Reload reloads the global manager's themes, see Manager.Reload
+func Reload() []error {
+	return globalManager.Reload()
+}
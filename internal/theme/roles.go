@@ -0,0 +1,124 @@
+package theme
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Role is one of the colors a theme sets
+type Role struct {
+	Key   string // where a theme file sets it, e.g. "games.chess.blackPieces"
+	Color func(Theme) lipgloss.TerminalColor
+}
+
+// TerminalBackgroundKey is the role of the terminal background, which a
+// theme file sets with the "bg" palette color
+const TerminalBackgroundKey = "palette.bg"
+
+// Roles lists every color of the Theme interface, in its order
+var Roles = []Role{
+	{"ui.primary", Theme.Primary},
+	{"ui.secondary", Theme.Secondary},
+	{"ui.accent", Theme.Accent},
+	{"ui.success", Theme.Success},
+	{"ui.warning", Theme.Warning},
+	{"ui.error", Theme.Error},
+
+	{"board.border", Theme.BoardBorder},
+	{"board.background", Theme.BoardBackground},
+	{"board.cellBorder", Theme.CellBorder},
+	{"board.cellBackground", Theme.CellBackground},
+	{"board.selectedCell", Theme.SelectedCell},
+
+	{"games.tictactoe.player1", Theme.Player1},
+	{"games.tictactoe.player2", Theme.Player2},
+	{"games.snake.body", Theme.SnakeBody},
+	{"games.snake.head", Theme.SnakeHead},
+	{"games.snake.food", Theme.Food},
+
+	{"games.chess.whitePieces", Theme.WhitePiece},
+	{"games.chess.blackPieces", Theme.BlackPiece},
+
+	{"games.tetris.iPiece", Theme.TetrisI},
+	{"games.tetris.oPiece", Theme.TetrisO},
+	{"games.tetris.tPiece", Theme.TetrisT},
+	{"games.tetris.sPiece", Theme.TetrisS},
+	{"games.tetris.zPiece", Theme.TetrisZ},
+	{"games.tetris.jPiece", Theme.TetrisJ},
+	{"games.tetris.lPiece", Theme.TetrisL},
+
+	{TerminalBackgroundKey, Theme.TerminalBackground},
+}
+
+// Setting returns what def sets the role to, empty if nothing
+func (r Role) Setting(def *ThemeDefinition) string {
+	if r.Key == TerminalBackgroundKey {
+		return def.Palette["bg"]
+	}
+	return r.field(def).String()
+}
+
+// Set sets the role in def, or leaves it to the theme def extends when
+// value is empty
+func (r Role) Set(def *ThemeDefinition, value string) {
+	if r.Key == TerminalBackgroundKey {
+		if value == "" {
+			delete(def.Palette, "bg")
+			return
+		}
+		if def.Palette == nil {
+			def.Palette = map[string]string{}
+		}
+		def.Palette["bg"] = value
+		return
+	}
+	r.field(def).SetString(value)
+}
+
+// field finds the role's setting in def by the yaml keys of its fields
+func (r Role) field(def *ThemeDefinition) reflect.Value {
+	v := reflect.ValueOf(def).Elem()
+next:
+	for _, key := range strings.Split(r.Key, ".") {
+		for i := range v.NumField() {
+			tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+			if tag == key {
+				v = v.Field(i)
+				continue next
+			}
+		}
+		panic(fmt.Sprintf("theme: no setting %s", r.Key))
+	}
+	return v
+}
+
+// ColorString describes a color the way theme files write it: a hex
+// color, "none", or for colors that adapt to the terminal background the
+// dark variant
+func ColorString(c lipgloss.TerminalColor) string {
+	switch c := c.(type) {
+	case lipgloss.Color:
+		if c == "" {
+			return "none"
+		}
+		return string(c)
+	case lipgloss.AdaptiveColor:
+		return c.Dark
+	case nil:
+		return "none"
+	}
+	return fmt.Sprint(c)
+}
+
+// FromDefinition makes a theme from a definition without registering it.
+// A theme it extends must be registered.
+func FromDefinition(def *ThemeDefinition) (Theme, error) {
+	files, errs := globalManager.resolveThemes([]themeFile{{def: def, path: def.Name}})
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return files[0].theme, nil
+}
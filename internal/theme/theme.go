@@ -83,6 +83,11 @@ type BaseTheme struct {
 
 	terminalBackground    lipgloss.TerminalColor
 	useTerminalBackground bool
+
+	// A theme loaded from YAML keeps its definition, merged with the
+	// themes it extends, and the theme supplying the colors it leaves out
+	def  *ThemeDefinition
+	base *BaseTheme
 }
 
 func (t *BaseTheme) Name() string { return t.name }
@@ -125,3 +130,40 @@ func (t *BaseTheme) TetrisL() lipgloss.TerminalColor { return t.tetrisL }
 // Terminal Background
 func (t *BaseTheme) TerminalBackground() lipgloss.TerminalColor { return t.terminalBackground }
 func (t *BaseTheme) ShouldUseTerminalBackground() bool          { return t.useTerminalBackground }
+
+// baseOf returns the colors of any theme as a BaseTheme
+func baseOf(t Theme) *BaseTheme {
+	if base, ok := t.(*BaseTheme); ok {
+		return base
+	}
+	return &BaseTheme{
+		name:                  t.Name(),
+		primary:               t.Primary(),
+		secondary:             t.Secondary(),
+		accent:                t.Accent(),
+		success:               t.Success(),
+		warning:               t.Warning(),
+		error:                 t.Error(),
+		boardBorder:           t.BoardBorder(),
+		boardBackground:       t.BoardBackground(),
+		cellBorder:            t.CellBorder(),
+		cellBackground:        t.CellBackground(),
+		selectedCell:          t.SelectedCell(),
+		player1:               t.Player1(),
+		player2:               t.Player2(),
+		snakeBody:             t.SnakeBody(),
+		snakeHead:             t.SnakeHead(),
+		food:                  t.Food(),
+		whitePiece:            t.WhitePiece(),
+		blackPiece:            t.BlackPiece(),
+		tetrisI:               t.TetrisI(),
+		tetrisO:               t.TetrisO(),
+		tetrisT:               t.TetrisT(),
+		tetrisS:               t.TetrisS(),
+		tetrisZ:               t.TetrisZ(),
+		tetrisJ:               t.TetrisJ(),
+		tetrisL:               t.TetrisL(),
+		terminalBackground:    t.TerminalBackground(),
+		useTerminalBackground: t.ShouldUseTerminalBackground(),
+	}
+}
@@ -0,0 +1,313 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is a mistake in a theme file
+type Problem struct {
+	Path    string
+	Line    int // from 1, 0 when unknown
+	Column  int
+	Message string
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.Line == 0:
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	case p.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", p.Path, p.Line, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.Path, p.Line, p.Column, p.Message)
+}
+
+// ValidateFile checks the theme file at path, see Validate
+func ValidateFile(path string, inherited map[string]string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme file %s: %w", path, err)
+	}
+	return Validate(data, path, inherited), nil
+}
+
+// Validate checks a theme file for unknown keys, colors that are neither
+// hex nor in the palette, and a missing name. Unlike loading the theme,
+// which ignores such mistakes, it reports every one of them. path names
+// the file in problems. inherited is the palette of the theme it extends,
+// whose colors it can use by name too, see ValidateExtends.
+func Validate(data []byte, path string, inherited map[string]string) []Problem {
+	v := &validator{path: path}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		v.problems = append(v.problems, syntaxProblem(path, err))
+		return v.problems
+	}
+	if len(doc.Content) == 0 {
+		v.report(&doc, "missing theme name")
+		return v.problems
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		v.report(root, "expected a mapping of theme settings")
+		return v.problems
+	}
+
+	v.palette = map[string]bool{}
+	for name := range inherited {
+		v.palette[name] = true
+	}
+	if palette := lookup(root, "palette"); palette != nil && palette.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(palette.Content); i += 2 {
+			v.palette[palette.Content[i].Value] = true
+		}
+	}
+
+	if name := lookup(root, "name"); name == nil || strings.TrimSpace(name.Value) == "" {
+		v.report(root, "missing theme name")
+	}
+	v.mapping(root, reflect.TypeOf(ThemeDefinition{}), "")
+	return v.problems
+}
+
+// ValidateExtends follows what the themes in paths extend, the way loading
+// them does, and reports themes that extend each other or a theme that
+// doesn't exist. The user's themes and the built-in ones can be extended.
+// It also returns the palette each theme inherits, by path, for Validate.
+func ValidateExtends(paths []string) ([]Problem, map[string]map[string]string) {
+	m := &Manager{
+		themes:  make(map[string]Theme),
+		sources: make(map[string][]string),
+	}
+	m.loadBuiltin()
+
+	// The files to validate come last, so they win over user themes of
+	// the same name
+	validated := map[string]bool{}
+	for _, path := range paths {
+		validated[absPath(path)] = true
+	}
+	var files []themeFile
+	add := func(path string) {
+		// Files that can't be parsed are reported by Validate
+		if def, err := readThemeFile(path); err == nil {
+			files = append(files, themeFile{def: def, path: path})
+		}
+	}
+	for _, path := range UserThemeFiles() {
+		if !validated[absPath(path)] {
+			add(path)
+		}
+	}
+	firstValidated := len(files)
+	for _, path := range paths {
+		add(path)
+	}
+
+	r := m.newResolver(files)
+	var problems []Problem
+	inherited := map[string]map[string]string{}
+	for i := firstValidated; i < len(files); i++ {
+		def := files[i].def
+		_, err := r.resolve(&files[i], nil)
+		if err == nil && def.Extends != "" {
+			if parent, err := r.parent(def, []string{def.Name}); err == nil && parent.def != nil {
+				inherited[files[i].path] = parent.def.Palette
+			}
+		}
+		var extendsErr *ExtendsError
+		if !errors.As(err, &extendsErr) {
+			continue
+		}
+		problem := Problem{Path: files[i].path, Message: extendsErr.Error()}
+		if node := extendsNode(files[i].path); node != nil {
+			problem.Line, problem.Column = node.Line, node.Column
+		}
+		problems = append(problems, problem)
+	}
+	return problems, inherited
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// extendsNode returns the value of extends in a theme file, for its
+// position
+func extendsNode(path string) *yaml.Node {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	return lookup(doc.Content[0], "extends")
+}
+
+// syntaxProblem turns a YAML parse error into a problem, keeping the line
+// yaml puts in its message
+func syntaxProblem(path string, err error) Problem {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	var line int
+	if rest, ok := strings.CutPrefix(message, "line "); ok {
+		if number, text, ok := strings.Cut(rest, ": "); ok {
+			if n, err := strconv.Atoi(number); err == nil {
+				line, message = n, text
+			}
+		}
+	}
+	return Problem{Path: path, Line: line, Message: message}
+}
+
+type validator struct {
+	path     string
+	palette  map[string]bool
+	problems []Problem
+}
+
+func (v *validator) report(node *yaml.Node, format string, args ...any) {
+	v.problems = append(v.problems, Problem{
+		Path:    v.path,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// mapping checks a mapping against the struct it's decoded into. prefix
+// is the dotted path of the mapping, for messages.
+func (v *validator) mapping(node *yaml.Node, t reflect.Type, prefix string) {
+	fields := map[string]reflect.Type{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		fields[key] = field.Type
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		name := prefix + key.Value
+
+		fieldType, known := fields[key.Value]
+		if !known {
+			v.unknownKey(key, name, fields)
+			continue
+		}
+		if value.Tag == "!!null" {
+			continue
+		}
+
+		switch {
+		case fieldType.Kind() == reflect.Struct:
+			if value.Kind != yaml.MappingNode {
+				v.report(value, "%s: expected a mapping", name)
+				continue
+			}
+			v.mapping(value, fieldType, name+".")
+		case fieldType.Kind() == reflect.Map:
+			v.paletteColors(value, name)
+		case name == "name" || name == "extends":
+			if value.Kind != yaml.ScalarNode {
+				v.report(value, "%s: expected a theme name", name)
+			}
+		default:
+			v.color(value, name)
+		}
+	}
+}
+
+func (v *validator) unknownKey(key *yaml.Node, name string, fields map[string]reflect.Type) {
+	for field := range fields {
+		if strings.EqualFold(field, key.Value) {
+			v.report(key, "unknown key %q, did you mean %q?", name, field)
+			return
+		}
+	}
+	v.report(key, "unknown key %q", name)
+}
+
+// paletteColors checks the palette, whose colors must be given directly
+func (v *validator) paletteColors(node *yaml.Node, name string) {
+	if node.Kind != yaml.MappingNode {
+		v.report(node, "%s: expected a mapping of color names to colors", name)
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		colorName := name + "." + key.Value
+		if value.Kind != yaml.ScalarNode {
+			v.report(value, "%s: expected a color", colorName)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(value.Value, "#"):
+			v.hex(value, colorName)
+		case !isANSIColor(value.Value):
+			v.report(value, "%s: invalid color %q, expected a hex color such as \"#ff79c6\"", colorName, value.Value)
+		}
+	}
+}
+
+// color checks a color setting: a hex color, an ANSI color number,
+// "none", or the name of a palette color
+func (v *validator) color(node *yaml.Node, name string) {
+	if node.Kind != yaml.ScalarNode {
+		v.report(node, "%s: expected a color", name)
+		return
+	}
+	value := node.Value
+	switch {
+	case value == "" || value == "none" || isANSIColor(value):
+	case strings.HasPrefix(value, "#"):
+		v.hex(node, name)
+	case !v.palette[value]:
+		v.report(node, "%s: unknown palette color %q", name, value)
+	}
+}
+
+func (v *validator) hex(node *yaml.Node, name string) {
+	if !IsHexColor(node.Value) {
+		v.report(node, "%s: invalid hex color %q, expected #rgb or #rrggbb", name, node.Value)
+	}
+}
+
+// IsHexColor reports whether s is a color such as "#f8f8f2" or "#fff"
+func IsHexColor(s string) bool {
+	digits, ok := strings.CutPrefix(s, "#")
+	if !ok || (len(digits) != 3 && len(digits) != 6) {
+		return false
+	}
+	_, err := strconv.ParseUint(digits, 16, 32)
+	return err == nil
+}
+
+// isANSIColor reports whether s is a terminal color number, 0 to 255
+func isANSIColor(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255 && s == strconv.Itoa(n)
+}
+
+// lookup returns the value of key in a mapping node
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeThemes writes theme files to a temporary directory, with no user
+// themes around them, and returns their paths in order
+func writeThemes(t *testing.T, files ...string) []string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ThemePathEnv, "")
+
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i+1 < len(files); i += 2 {
+		path := filepath.Join(dir, files[i])
+		if err := os.WriteFile(path, []byte(files[i+1]), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// validate runs the checks theme validate runs on the files, returning
+// the problems found with the files' base names
+func validate(t *testing.T, paths []string) []string {
+	t.Helper()
+	problems, inherited := ValidateExtends(paths)
+	for _, path := range paths {
+		fileProblems, err := ValidateFile(path, inherited[path])
+		if err != nil {
+			t.Fatal(err)
+		}
+		problems = append(problems, fileProblems...)
+	}
+
+	var messages []string
+	for _, problem := range problems {
+		problem.Path = filepath.Base(problem.Path)
+		messages = append(messages, problem.String())
+	}
+	slices.Sort(messages)
+	return messages
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name: "palette colors",
+			files: []string{"a.yaml", `name: a
+palette:
+  pink: "#ff79c6"
+ui:
+  accent: pink
+  primary: blue
+`},
+			want: []string{`a.yaml:6:12: ui.primary: unknown palette color "blue"`},
+		},
+		{
+			name: "colors from a built-in theme",
+			files: []string{"dracula-alt.yaml", `name: dracula-alt
+extends: dracula
+ui:
+  accent: pink
+  primary: teal
+`},
+			want: []string{`dracula-alt.yaml:5:12: ui.primary: unknown palette color "teal"`},
+		},
+		{
+			name: "colors from another file",
+			files: []string{
+				"a.yaml", "name: a\nextends: b\nui:\n  accent: pink\n",
+				"b.yaml", "name: b\npalette:\n  pink: \"#ff79c6\"\n",
+			},
+		},
+		{
+			name: "colors from a theme extending its own name",
+			files: []string{"nord.yaml", `name: nord
+extends: nord
+palette:
+  pink: "#ff79c6"
+ui:
+  accent: pink
+  primary: nord8
+`},
+		},
+		{
+			name: "cycle",
+			files: []string{
+				"a.yaml", "name: a\nextends: b\n",
+				"b.yaml", "name: b\nextends: a\n",
+			},
+			want: []string{
+				`a.yaml:2:10: extends "b": cycle a → b → a`,
+				`b.yaml:2:10: extends "a": cycle b → a → b`,
+			},
+		},
+		{
+			name:  "missing parent",
+			files: []string{"a.yaml", "name: a\nextends: nope\nui:\n  accent: pink\n"},
+			want: []string{
+				`a.yaml:2:10: extends "nope": parent "nope" not found`,
+				`a.yaml:4:11: ui.accent: unknown palette color "pink"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validate(t, writeThemes(t, tt.files...)); !slices.Equal(got, tt.want) {
+				t.Errorf("problems:\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package theme
+
+import (
+	"maps"
+	"os"
+	"sync"
+	"time"
+)
+
+// WatchInterval is how often Watch looks for changed theme files
+const WatchInterval = 500 * time.Millisecond
+
+// fileStamp tells versions of a file apart
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch reloads the themes into the global manager whenever a user theme
+// file is added, changed or removed, then calls changed with the themes
+// that couldn't be loaded. It checks every interval until stopped.
+func Watch(interval time.Duration, changed func(errs []error)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := userThemeStamps()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			stamps := userThemeStamps()
+			if maps.Equal(stamps, last) {
+				continue
+			}
+			last = stamps
+			changed(Reload())
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// userThemeStamps returns the current version of every user theme file
+func userThemeStamps() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, path := range UserThemeFiles() {
+		if info, err := os.Stat(path); err == nil {
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
@@ -0,0 +1,24 @@
+package tournament
+
+import "math"
+
+const (
+	// InitialRating is every entrant's rating before the first game
+	InitialRating = 1500
+
+	// eloK is how far a single game moves a rating
+	eloK = 32
+)
+
+// expectedScore is a's expected score against b, from 0 to 1
+func expectedScore(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// rate updates both ratings after a game in which white scored score
+// (1 for a win, 0.5 for a draw, 0 for a loss)
+func rate(white, black *Entrant, score float64) {
+	expected := expectedScore(white.Rating, black.Rating)
+	white.Rating += eloK * (score - expected)
+	black.Rating += eloK * ((1 - score) - (1 - expected))
+}
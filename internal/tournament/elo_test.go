@@ -0,0 +1,84 @@
+package tournament
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+func TestExpectedScore(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want float64
+	}{
+		{1500, 1500, 0.5},
+		{1900, 1500, 0.909},
+		{1500, 1900, 0.091},
+		{1700, 1500, 0.760},
+		{1500, 1700, 0.240},
+	}
+	for _, tt := range tests {
+		if got := expectedScore(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("expectedScore(%v, %v) = %.3f, want %.3f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		name         string
+		white, black float64
+		score        float64
+		wantWhite    float64
+		wantBlack    float64
+	}{
+		{"equal, white wins", 1500, 1500, 1, 1516, 1484},
+		{"equal, draw", 1500, 1500, 0.5, 1500, 1500},
+		{"equal, black wins", 1500, 1500, 0, 1484, 1516},
+		{"favorite wins", 1700, 1500, 1, 1707.688, 1492.312},
+		{"favorite draws", 1700, 1500, 0.5, 1691.688, 1508.312},
+		{"upset", 1700, 1500, 0, 1675.688, 1524.312},
+		{"underdog wins", 1500, 1700, 1, 1524.312, 1675.688},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			white := &Entrant{Rating: tt.white}
+			black := &Entrant{Rating: tt.black}
+			rate(white, black, tt.score)
+			if math.Abs(white.Rating-tt.wantWhite) > 0.001 || math.Abs(black.Rating-tt.wantBlack) > 0.001 {
+				t.Errorf("ratings %.3f and %.3f, want %.3f and %.3f", white.Rating, black.Rating, tt.wantWhite, tt.wantBlack)
+			}
+			// Points move from one entrant to the other
+			if sum := white.Rating + black.Rating; math.Abs(sum-(tt.white+tt.black)) > 1e-9 {
+				t.Errorf("ratings sum to %v, want %v", sum, tt.white+tt.black)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		outcome                  engine.Outcome
+		whitePoints, blackPoints float64
+		whiteRating, blackRating float64
+	}{
+		{engine.OutcomeWin, 1, 0, 1516, 1484},
+		{engine.OutcomeLoss, 0, 1, 1484, 1516},
+		{engine.OutcomeDraw, 0.5, 0.5, 1500, 1500},
+	}
+	for _, tt := range tests {
+		tournament := newTestTournament(t, RoundRobin, 2)
+		tournament.record(&Game{Pairing: Pairing{Round: 1, Board: 1, White: 1, Black: 0}, Outcome: tt.outcome})
+		white, black := tournament.Entrants[1], tournament.Entrants[0]
+		if white.Points != tt.whitePoints || black.Points != tt.blackPoints {
+			t.Errorf("%v: points %v and %v, want %v and %v", tt.outcome, white.Points, black.Points, tt.whitePoints, tt.blackPoints)
+		}
+		if white.Rating != tt.whiteRating || black.Rating != tt.blackRating {
+			t.Errorf("%v: ratings %v and %v, want %v and %v", tt.outcome, white.Rating, black.Rating, tt.whiteRating, tt.blackRating)
+		}
+		if white.Played != 1 || black.Played != 1 || white.Wins+white.Draws+white.Losses != 1 {
+			t.Errorf("%v: played %d and %d", tt.outcome, white.Played, black.Played)
+		}
+	}
+}
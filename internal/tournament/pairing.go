@@ -0,0 +1,173 @@
+package tournament
+
+import (
+	"slices"
+)
+
+// Pairing is a game between two entrants, by index. Black is -1 for a bye.
+type Pairing struct {
+	Round, Board int // from 1
+	White, Black int
+}
+
+// Bye reports whether White sits the round out
+func (p Pairing) Bye() bool {
+	return p.Black < 0
+}
+
+// roundRobin pairs everyone with everyone once per cycle, using the
+// circle method. Colors swap from one cycle to the next.
+func roundRobin(entrants, cycles int) [][]Pairing {
+	players := make([]int, entrants)
+	for i := range players {
+		players[i] = i
+	}
+	if entrants%2 == 1 {
+		players = append(players, -1)
+	}
+	n := len(players)
+
+	var rounds [][]Pairing
+	for cycle := range cycles {
+		circle := slices.Clone(players)
+		for r := range n - 1 {
+			var round []Pairing
+			for i := range n / 2 {
+				white, black := circle[i], circle[n-1-i]
+				// Alternate the fixed player's color between rounds
+				if (i == 0 && r%2 == 1) != (cycle%2 == 1) {
+					white, black = black, white
+				}
+				if white < 0 {
+					white, black = black, white
+				}
+				round = append(round, Pairing{White: white, Black: black})
+			}
+			// List the bye last, like Swiss rounds do
+			slices.SortStableFunc(round, func(a, b Pairing) int {
+				switch {
+				case a.Bye() && !b.Bye():
+					return 1
+				case b.Bye() && !a.Bye():
+					return -1
+				}
+				return 0
+			})
+			rounds = append(rounds, numbered(round, len(rounds)+1))
+
+			// Keep the first player in place and rotate the rest
+			last := circle[n-1]
+			copy(circle[2:], circle[1:n-1])
+			circle[1] = last
+		}
+	}
+	return rounds
+}
+
+// swiss pairs entrants with equal or close scores who haven't met yet.
+// With an odd number of entrants the lowest ranked one without a bye
+// sits out.
+func swiss(standings []*Entrant, round int) []Pairing {
+	ranked := slices.Clone(standings)
+	slices.SortStableFunc(ranked, compareEntrants)
+
+	var pairings []Pairing
+	if len(ranked)%2 == 1 {
+		bye := len(ranked) - 1
+		for i := len(ranked) - 1; i >= 0; i-- {
+			if !ranked[i].hadBye {
+				bye = i
+				break
+			}
+		}
+		pairings = append(pairings, Pairing{White: ranked[bye].index, Black: -1})
+		ranked = slices.Delete(ranked, bye, bye+1)
+	}
+
+	opponents := pairUp(ranked)
+	var games []Pairing
+	for i, a := range ranked {
+		j := opponents[i]
+		if j < i {
+			continue
+		}
+		white, black := a, ranked[j]
+		if black.whites < white.whites || (black.whites == white.whites && round%2 == 0) {
+			white, black = black, white
+		}
+		games = append(games, Pairing{White: white.index, Black: black.index})
+	}
+
+	return numbered(append(games, pairings...), round)
+}
+
+// pairSearchLimit bounds the search for pairings without rematches, which
+// may not exist once most entrants have met
+const pairSearchLimit = 100000
+
+// pairUp pairs an even number of ranked entrants, returning the index of
+// each one's opponent. Entrants are paired as close in rank as possible
+// with someone they haven't met; when that can't be done, rematches are
+// allowed.
+func pairUp(ranked []*Entrant) []int {
+	opponents := make([]int, len(ranked))
+	for i := range opponents {
+		opponents[i] = -1
+	}
+
+	steps := 0
+	var search func(rematches bool) bool
+	search = func(rematches bool) bool {
+		i := slices.Index(opponents, -1)
+		if i < 0 {
+			return true
+		}
+		for j := i + 1; j < len(ranked); j++ {
+			if opponents[j] >= 0 || (!rematches && slices.Contains(ranked[i].opponents, ranked[j].index)) {
+				continue
+			}
+			if steps++; steps > pairSearchLimit && !rematches {
+				return false
+			}
+			opponents[i], opponents[j] = j, i
+			if search(rematches) {
+				return true
+			}
+			opponents[i], opponents[j] = -1, -1
+		}
+		return false
+	}
+
+	if !search(false) {
+		for i := range opponents {
+			opponents[i] = -1
+		}
+		search(true)
+	}
+	return opponents
+}
+
+func numbered(pairings []Pairing, round int) []Pairing {
+	for i := range pairings {
+		pairings[i].Round = round
+		pairings[i].Board = i + 1
+	}
+	return pairings
+}
+
+// compareEntrants orders entrants by points, then rating
+func compareEntrants(a, b *Entrant) int {
+	if a.Points != b.Points {
+		if a.Points > b.Points {
+			return -1
+		}
+		return 1
+	}
+	if a.Rating != b.Rating {
+		if a.Rating > b.Rating {
+			return -1
+		}
+		return 1
+	}
+	return a.index - b.index
+}
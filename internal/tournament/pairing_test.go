@@ -0,0 +1,199 @@
+package tournament
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jakmaz/arcade/internal/engine"
+)
+
+// newTestTournament enters n bots, named bot0 to bot(n-1)
+func newTestTournament(t *testing.T, format string, n int) *Tournament {
+	t.Helper()
+	var commands []string
+	for i := range n {
+		commands = append(commands, fmt.Sprintf("bot%d", i))
+	}
+	tournament, err := New(Config{Format: format}, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tournament
+}
+
+// meeting names the two entrants of a pairing, whatever their colors
+func meeting(p Pairing) [2]int {
+	return [2]int{min(p.White, p.Black), max(p.White, p.Black)}
+}
+
+func TestRoundRobin(t *testing.T) {
+	for entrants := 2; entrants <= 7; entrants++ {
+		for cycles := 1; cycles <= 2; cycles++ {
+			t.Run(fmt.Sprintf("%d entrants, %d cycles", entrants, cycles), func(t *testing.T) {
+				rounds := roundRobin(entrants, cycles)
+				perCycle := entrants - 1 + entrants%2
+				if len(rounds) != perCycle*cycles {
+					t.Fatalf("%d rounds, want %d", len(rounds), perCycle*cycles)
+				}
+
+				games := map[[2]int][]Pairing{}
+				byes := map[int]int{}
+				for r, round := range rounds {
+					seen := map[int]bool{}
+					for b, p := range round {
+						if p.Round != r+1 || p.Board != b+1 {
+							t.Errorf("pairing numbered round %d board %d, want %d and %d", p.Round, p.Board, r+1, b+1)
+						}
+						if seen[p.White] || seen[p.Black] {
+							t.Errorf("round %d: an entrant plays twice", r+1)
+						}
+						seen[p.White] = true
+						if p.Bye() {
+							byes[p.White]++
+							if b != len(round)-1 {
+								t.Errorf("round %d: bye on board %d, not last", r+1, b+1)
+							}
+							continue
+						}
+						seen[p.Black] = true
+						games[meeting(p)] = append(games[meeting(p)], p)
+					}
+				}
+
+				// Everyone meets everyone once a cycle, swapping colors
+				// in the next cycle
+				if len(games) != entrants*(entrants-1)/2 {
+					t.Errorf("%d pairs met, want %d", len(games), entrants*(entrants-1)/2)
+				}
+				for pair, met := range games {
+					if len(met) != cycles {
+						t.Errorf("%v met %d times, want %d", pair, len(met), cycles)
+					}
+					if cycles == 2 && met[0].White == met[1].White {
+						t.Errorf("%v had the same colors in both cycles", pair)
+					}
+				}
+				for entrant, n := range byes {
+					if entrants%2 == 0 || n != cycles {
+						t.Errorf("entrant %d had %d byes", entrant, n)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSwissFirstRound(t *testing.T) {
+	tests := []struct {
+		entrants int
+		want     []Pairing
+	}{
+		{2, []Pairing{{1, 1, 0, 1}}},
+		{4, []Pairing{{1, 1, 0, 1}, {1, 2, 2, 3}}},
+		// The lowest ranked entrant sits out, listed last
+		{5, []Pairing{{1, 1, 0, 1}, {1, 2, 2, 3}, {1, 3, 4, -1}}},
+	}
+	for _, tt := range tests {
+		tournament := newTestTournament(t, Swiss, tt.entrants)
+		if got := swiss(tournament.Entrants, 1); !slices.Equal(got, tt.want) {
+			t.Errorf("%d entrants: pairings %v, want %v", tt.entrants, got, tt.want)
+		}
+	}
+}
+
+func TestSwissPairsByScore(t *testing.T) {
+	tournament := newTestTournament(t, Swiss, 4)
+	// bot1 and bot3 win the first round
+	tournament.record(&Game{Pairing: Pairing{Round: 1, Board: 1, White: 0, Black: 1}, Outcome: engine.OutcomeLoss})
+	tournament.record(&Game{Pairing: Pairing{Round: 1, Board: 2, White: 2, Black: 3}, Outcome: engine.OutcomeLoss})
+
+	round := swiss(tournament.Entrants, 2)
+	got := []([2]int){meeting(round[0]), meeting(round[1])}
+	want := []([2]int){{1, 3}, {0, 2}}
+	if !slices.Equal(got, want) {
+		t.Errorf("round 2 meetings %v, want the winners and the losers %v", got, want)
+	}
+	// Both sides of each game had the same color, so the lower ranked
+	// one takes white in even rounds
+	if round[0].White != 3 || round[1].White != 2 {
+		t.Errorf("round 2 whites %d and %d, want 3 and 2", round[0].White, round[1].White)
+	}
+}
+
+// Plays Swiss tournaments through, checking that nobody meets the same
+// opponent twice or gets a second bye while there is still someone new
+// to play
+func TestSwissNoRematches(t *testing.T) {
+	outcomes := map[string]func(p Pairing) engine.Outcome{
+		"white wins": func(Pairing) engine.Outcome { return engine.OutcomeWin },
+		"lower index wins": func(p Pairing) engine.Outcome {
+			if p.White < p.Black {
+				return engine.OutcomeWin
+			}
+			return engine.OutcomeLoss
+		},
+		"draws": func(Pairing) engine.Outcome { return engine.OutcomeDraw },
+	}
+	for name, outcome := range outcomes {
+		for entrants := 2; entrants <= 16; entrants++ {
+			rounds := max(entrants/2, 1)
+			t.Run(fmt.Sprintf("%s, %d entrants", name, entrants), func(t *testing.T) {
+				tournament := newTestTournament(t, Swiss, entrants)
+				met := map[[2]int]bool{}
+				byes := map[int]bool{}
+				for round := 1; round <= rounds; round++ {
+					pairings := swiss(tournament.Entrants, round)
+					if want := (entrants + 1) / 2; len(pairings) != want {
+						t.Fatalf("round %d: %d pairings, want %d", round, len(pairings), want)
+					}
+					for _, p := range pairings {
+						game := &Game{Pairing: p}
+						if p.Bye() {
+							if byes[p.White] {
+								t.Errorf("round %d: second bye for %d", round, p.White)
+							}
+							byes[p.White] = true
+						} else {
+							if met[meeting(p)] {
+								t.Errorf("round %d: %v meet again", round, meeting(p))
+							}
+							met[meeting(p)] = true
+							game.Outcome = outcome(p)
+						}
+						tournament.record(game)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Once everyone has met, entrants play again rather than not at all
+func TestSwissRematchesWhenNeeded(t *testing.T) {
+	tournament := newTestTournament(t, Swiss, 2)
+	for round := 1; round <= 3; round++ {
+		pairings := swiss(tournament.Entrants, round)
+		if len(pairings) != 1 || meeting(pairings[0]) != [2]int{0, 1} {
+			t.Fatalf("round %d: pairings %v, want 0 against 1", round, pairings)
+		}
+		tournament.record(&Game{Pairing: pairings[0], Outcome: engine.OutcomeDraw})
+	}
+}
+
+func TestSwissByePoint(t *testing.T) {
+	tests := []struct {
+		format string
+		points float64
+	}{
+		{Swiss, 1},
+		{RoundRobin, 0},
+	}
+	for _, tt := range tests {
+		tournament := newTestTournament(t, tt.format, 3)
+		tournament.record(&Game{Pairing: Pairing{Round: 1, Board: 2, White: 2, Black: -1}})
+		if got := tournament.Entrants[2]; got.Points != tt.points || got.Played != 0 {
+			t.Errorf("%s: bye gave %v points and %d games, want %v and 0", tt.format, got.Points, got.Played, tt.points)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// Package tournament plays bots against each other in round-robin or
+// Swiss tournaments and rates them with the Elo system
+package tournament
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jakmaz/arcade/internal/bot"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/engine"
+	"github.com/jakmaz/arcade/internal/games/chess"
+	"github.com/jakmaz/arcade/internal/replay"
+	"github.com/jakmaz/arcade/internal/storage"
+)
+
+// Formats are the supported pairing systems
+const (
+	RoundRobin = "round-robin"
+	Swiss      = "swiss"
+)
+
+// Config describes a tournament
+type Config struct {
+	Game     string
+	Options  core.Options
+	Format   string // RoundRobin or Swiss
+	Rounds   int    // cycles of a round-robin, or rounds of a Swiss tournament
+	MoveTime time.Duration
+	MaxTurns int
+	Parallel int    // games played at once
+	Dir      string // where games are written, empty for nowhere
+}
+
+// Entrant is a bot in the tournament and its standing
+type Entrant struct {
+	Name    string
+	Command string
+	Rating  float64
+	Points  float64 // 1 per win or Swiss bye, ½ per draw
+	Played  int
+	Wins    int
+	Draws   int
+	Losses  int
+
+	index     int
+	opponents []int
+	whites    int
+	hadBye    bool
+}
+
+// Game is a finished tournament game
+type Game struct {
+	Pairing
+	Outcome engine.Outcome // for White
+	Reason  string         // why the game ended early, if it did
+	Turns   int
+	Files   []string // PGN and replay, when written
+}
+
+// Tournament is a tournament in progress
+type Tournament struct {
+	Config
+	Entrants []*Entrant
+	Games    []*Game
+}
+
+// New enters the bots, given as commands. Each entrant is named after
+// its program, numbered if several share a name.
+func New(config Config, commands []string) (*Tournament, error) {
+	if len(commands) < 2 {
+		return nil, fmt.Errorf("a tournament needs at least two bots")
+	}
+	if config.Format != RoundRobin && config.Format != Swiss {
+		return nil, fmt.Errorf("unknown tournament format '%s' (expected %s or %s)", config.Format, RoundRobin, Swiss)
+	}
+
+	t := &Tournament{Config: config}
+	t.Rounds = max(t.Rounds, 1)
+	t.Parallel = max(t.Parallel, 1)
+	for i, command := range commands {
+		fields := strings.Fields(command)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("empty bot command")
+		}
+		name := filepath.Base(fields[len(fields)-1])
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+		t.Entrants = append(t.Entrants, &Entrant{Name: name, Command: command, Rating: InitialRating, index: i})
+	}
+
+	// Tell entrants sharing a name apart
+	count := map[string]int{}
+	for _, e := range t.Entrants {
+		count[e.Name]++
+	}
+	for i, e := range t.Entrants {
+		if count[e.Name] > 1 {
+			e.Name = fmt.Sprintf("%s-%d", e.Name, i+1)
+		}
+	}
+	return t, nil
+}
+
+// Run plays every round, calling played after each game in pairing
+// order. Games within a round-robin, or within a Swiss round, are played
+// in parallel.
+func (t *Tournament) Run(played func(*Game)) error {
+	if t.Format == RoundRobin {
+		var pairings []Pairing
+		for _, round := range roundRobin(len(t.Entrants), t.Rounds) {
+			pairings = append(pairings, round...)
+		}
+		return t.play(pairings, played)
+	}
+
+	for round := range t.Rounds {
+		if err := t.play(swiss(t.Entrants, round+1), played); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// play runs the pairings and records their results in order, so the
+// ratings don't depend on which game finished first
+func (t *Tournament) play(pairings []Pairing, played func(*Game)) error {
+	games := make([]*Game, len(pairings))
+	errs := make([]error, len(pairings))
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for range t.Parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				games[i], errs[i] = t.playGame(pairings[i])
+			}
+		}()
+	}
+	for i := range pairings {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for i, game := range games {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		t.record(game)
+		if played != nil {
+			played(game)
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) playGame(pairing Pairing) (*Game, error) {
+	game := &Game{Pairing: pairing}
+	if pairing.Bye() {
+		return game, nil
+	}
+
+	white, black := t.Entrants[pairing.White], t.Entrants[pairing.Black]
+	var bots []*bot.Bot
+	defer func() {
+		for _, b := range bots {
+			b.Close()
+		}
+	}()
+	for _, entrant := range []*Entrant{white, black} {
+		b, err := bot.Start(entrant.Command, io.Discard)
+		if err != nil {
+			return nil, err
+		}
+		b.Name = entrant.Name
+		bots = append(bots, b)
+	}
+
+	options := core.Options{core.SeedOption: core.NewSeed()}
+	for key, value := range t.Options {
+		options[key] = value
+	}
+	_, match, err := bot.NewGame(t.Game, options, bots)
+	if err != nil {
+		return nil, err
+	}
+	if len(match.Players()) != 2 {
+		return nil, fmt.Errorf("%s is not a two-player game", t.Game)
+	}
+	match.Timeout = t.MoveTime
+	match.MaxTurns = t.MaxTurns
+	match.Replay = replay.New(t.Game, options)
+
+	result := match.Run()
+	game.Outcome = result.Outcome
+	game.Reason = match.Reason()
+	game.Turns = match.Turns()
+
+	if t.Dir != "" {
+		if err := t.write(game, match); err != nil {
+			return nil, err
+		}
+	}
+	return game, nil
+}
+
+// write stores the game's replay, and its PGN for chess
+func (t *Tournament) write(game *Game, match *bot.Match) error {
+	white, black := t.Entrants[game.White], t.Entrants[game.Black]
+	base := filepath.Join(t.Dir, "games", fmt.Sprintf("r%02d-b%02d-%s-%s", game.Round, game.Board, white.Name, black.Name))
+
+	path := base + ".json"
+	if err := replay.WriteFile(path, match.Replay); err != nil {
+		return err
+	}
+	game.Files = append(game.Files, path)
+
+	if g, ok := match.Engine().(*chess.Game); ok {
+		tags := []chess.Tag{
+			{Name: "Event", Value: "arcade tournament"},
+			{Name: "Site", Value: "arcade"},
+			{Name: "Date", Value: time.Now().Format("2006.01.02")},
+			{Name: "Round", Value: fmt.Sprintf("%d.%d", game.Round, game.Board)},
+			{Name: "White", Value: white.Name},
+			{Name: "Black", Value: black.Name},
+			{Name: "Result", Value: game.Score()},
+		}
+		if game.Reason != "" {
+			tags = append(tags, chess.Tag{Name: "Termination", Value: game.Reason})
+		}
+
+		path := base + ".pgn"
+		if err := storage.WriteFile(path, []byte(g.PGN(tags...))); err != nil {
+			return err
+		}
+		game.Files = append(game.Files, path)
+	}
+	return nil
+}
+
+// record adds a game to the standings and ratings
+func (t *Tournament) record(game *Game) {
+	t.Games = append(t.Games, game)
+
+	white := t.Entrants[game.White]
+	if game.Bye() {
+		// Byes only come with a point in Swiss tournaments, where they
+		// stand in for a game
+		if t.Format == Swiss {
+			white.Points++
+		}
+		white.hadBye = true
+		return
+	}
+	black := t.Entrants[game.Black]
+
+	white.Played++
+	black.Played++
+	white.whites++
+	white.opponents = append(white.opponents, black.index)
+	black.opponents = append(black.opponents, white.index)
+
+	var score float64
+	switch game.Outcome {
+	case engine.OutcomeWin:
+		score = 1
+		white.Wins++
+		black.Losses++
+	case engine.OutcomeLoss:
+		black.Wins++
+		white.Losses++
+	default:
+		score = 0.5
+		white.Draws++
+		black.Draws++
+	}
+	white.Points += score
+	black.Points += 1 - score
+	rate(white, black, score)
+}
+
+// Score returns the result as written in PGN, e.g. "1-0"
+func (g *Game) Score() string {
+	switch g.Outcome {
+	case engine.OutcomeWin:
+		return "1-0"
+	case engine.OutcomeLoss:
+		return "0-1"
+	}
+	return "1/2-1/2"
+}
+
+// Standings returns the entrants ordered by points, then rating
+func (t *Tournament) Standings() []*Entrant {
+	standings := slices.Clone(t.Entrants)
+	slices.SortStableFunc(standings, compareEntrants)
+	return standings
+}
+
+// WriteStandings prints the standings table
+func (t *Tournament) WriteStandings(w io.Writer) {
+	fmt.Fprintf(w, "  %-4s %-20s %6s %5s %5s %5s %7s %6s\n", "#", "BOT", "PLAYED", "WON", "DRAWN", "LOST", "POINTS", "ELO")
+	for i, e := range t.Standings() {
+		fmt.Fprintf(w, "  %-4d %-20s %6d %5d %5d %5d %7.1f %6.0f\n",
+			i+1, e.Name, e.Played, e.Wins, e.Draws, e.Losses, e.Points, e.Rating)
+	}
+}
+
+// SaveStandings writes the standings table to the tournament directory
+func (t *Tournament) SaveStandings() (string, error) {
+	var b strings.Builder
+	games := 0
+	for _, game := range t.Games {
+		if !game.Bye() {
+			games++
+		}
+	}
+	fmt.Fprintf(&b, "%s %s tournament, %d games\n\n", t.Game, t.Format, games)
+	t.WriteStandings(&b)
+
+	path := filepath.Join(t.Dir, "standings.txt")
+	return path, storage.WriteFile(path, []byte(b.String()))
+}
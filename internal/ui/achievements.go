@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/achievements"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// achievementsScreen lists every achievement, opened from the menu
+type achievementsScreen struct {
+	progress achievements.Progress
+	err      error
+}
+
+func newAchievementsScreen() *achievementsScreen {
+	progress, err := achievements.Load()
+	return &achievementsScreen{progress: progress, err: err}
+}
+
+func (s *achievementsScreen) Update(msg tea.Msg) (screen, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q", "enter":
+			return nil, nil
+		}
+	}
+	return s, nil
+}
+
+func (s *achievementsScreen) View(width, height int) string {
+	var body string
+	if s.err != nil {
+		body = styles.GetErrorStyle().Render(s.err.Error())
+	} else {
+		all := achievements.All()
+		var lines []string
+		for _, a := range all {
+			date, unlocked := s.progress.Unlocked[a.ID]
+			if !unlocked {
+				lines = append(lines, styles.GetMenuItemStyle().Faint(true).Render(fmt.Sprintf("  %-14s %s", a.Name, a.Description)))
+				continue
+			}
+			lines = append(lines,
+				styles.GetSelectedItemStyle().Render(fmt.Sprintf("★ %-14s", a.Name))+" "+
+					styles.GetMenuItemStyle().Render(a.Description+"  "+date.Local().Format("2006-01-02")))
+		}
+
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.JoinVertical(lipgloss.Left, lines...),
+			"",
+			styles.GetMenuItemStyle().Render(fmt.Sprintf("%d of %d unlocked", len(s.progress.Unlocked), len(all))),
+		)
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		styles.GetTitleStyle().Render("Achievements"),
+		body,
+		styles.GetHelpStyle().Render("ESC to go back"),
+	)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -17,31 +17,41 @@ const (
 type App struct {
 	state       AppState
 	menu        tea.Model
-	currentGame tea.Model
+	currentGame *Session
+	toasts      toasts
 	width       int
 	height      int
 }
 
 func NewApp() *App {
+	useColorblind("")
 	return &App{
 		state: MenuState,
 		menu:  NewMenu(),
 	}
 }
 
-func NewAppWithGame(gameID string) *App {
+func NewAppWithGame(gameID string, options core.Options) (*App, error) {
+	session, err := NewSession(gameID, options)
+	if err != nil {
+		return nil, err
+	}
+
 	app := &App{
 		state:       GameState,
-		currentGame: core.CreateGame(gameID),
+		menu:        NewMenu(),
+		currentGame: session,
 		width:       80,
 		height:      25,
 	}
-	return app
+	return app, nil
 }
 
 // Custom messages for navigation
 type StartGameMsg struct {
-	GameID string
+	GameID  string
+	Options core.Options
+	Resume  bool // continue the saved game instead of starting a new one
 }
 
 type ReturnToMenuMsg struct{}
@@ -64,35 +74,54 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.menu, cmd = a.menu.Update(msg)
 			return a, cmd
 		} else {
-			var cmd tea.Cmd
-			a.currentGame, cmd = a.currentGame.Update(msg)
-			return a, cmd
+			return a, a.currentGame.Update(msg)
 		}
 
 	case tea.KeyMsg:
 		// Global Ctrl+C handling
 		if msg.String() == "ctrl+c" {
+			if a.state == GameState {
+				// Nowhere left to report a failure, so quit regardless
+				a.currentGame.Save()
+			}
 			return a, tea.Quit
 		}
 
+	case toastExpiredMsg:
+		if a.toasts.expire(msg) {
+			return a, nil
+		}
+
 	case StartGameMsg:
 		// Transition to game
-		a.currentGame = core.CreateGame(msg.GameID)
+		var session *Session
+		var err error
+		if msg.Resume {
+			session, err = ResumeSession(msg.GameID)
+		} else {
+			session, err = NewSession(msg.GameID, msg.Options)
+		}
+		if err != nil {
+			return a, a.toasts.push("Can't start "+msg.GameID, err.Error(), true)
+		}
+		a.currentGame = session
 		a.state = GameState
 
 		sizeMsg := tea.WindowSizeMsg{Width: a.width, Height: a.height}
-		a.currentGame, _ = a.currentGame.Update(sizeMsg)
-		return a, a.currentGame.Init()
+		sizeCmd := a.currentGame.Update(sizeMsg)
+		return a, tea.Batch(sizeCmd, a.currentGame.Init())
 
 	case ReturnToMenuMsg:
 		// Transition back to menu
 		a.state = MenuState
 		a.currentGame = nil
-		return a, nil
+		useColorblind("")
+		var cmd tea.Cmd
+		a.menu, cmd = a.menu.Update(msg)
+		return a, cmd
 
 	case ThemeChangedMsg:
-		styles.RefreshStyles()
-		return a, nil
+		return a, a.toasts.themeChanged(msg)
 	}
 
 	// Delegate to current state
@@ -110,6 +139,7 @@ func (a *App) View() string {
 	} else {
 		content = a.currentGame.View()
 	}
+	content = a.toasts.render(content)
 
 	// Apply terminal background with full viewport dimensions
 	if a.width > 0 && a.height > 0 {
@@ -125,31 +155,22 @@ func (a *App) View() string {
 }
 
 func (a *App) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Navigation messages returned by the menu come back through Update
 	var cmd tea.Cmd
 	a.menu, cmd = a.menu.Update(msg)
-
-	// Check if menu wants to start a game
-	if cmd != nil {
-		if msg := cmd(); msg != nil {
-			if startMsg, ok := msg.(StartGameMsg); ok {
-				return a.Update(startMsg)
-			}
-			if themeMsg, ok := msg.(ThemeChangedMsg); ok {
-				return a.Update(themeMsg)
-			}
-		}
-	}
-
 	return a, cmd
 }
 
 func (a *App) updateGame(msg tea.Msg) (tea.Model, tea.Cmd) {
-	// Check for ESC to return to menu
+	// Check for ESC to return to menu, keeping the game to continue later
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "esc" {
-		return a.Update(ReturnToMenuMsg{})
+		var toastCmd tea.Cmd
+		if err := a.currentGame.Save(); err != nil {
+			toastCmd = a.toasts.push("Game not saved", err.Error(), true)
+		}
+		model, cmd := a.Update(ReturnToMenuMsg{})
+		return model, tea.Batch(cmd, toastCmd)
 	}
 
-	var cmd tea.Cmd
-	a.currentGame, cmd = a.currentGame.Update(msg)
-	return a, cmd
+	return a, a.currentGame.Update(msg)
 }
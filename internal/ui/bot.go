@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/bot"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// BotViewer shows a game played by bots. The match drives the game's
+// engine directly, and the game only draws it.
+type BotViewer struct {
+	game          core.Game
+	match         *bot.Match
+	delay         time.Duration // between turns of turn-based games
+	thinking      bool
+	paused        bool
+	stepID        int
+	width, height int
+}
+
+// botStepMsg is due when the bots should be asked for the next turn
+type botStepMsg struct {
+	id int
+}
+
+// botTurnMsg carries the bots' answers for a turn
+type botTurnMsg struct {
+	turn bot.Turn
+}
+
+// NewBotViewer shows match being played on game, which must be the game
+// the match's engine belongs to. Turn-based games wait delay between
+// turns so they can be followed.
+func NewBotViewer(game core.Game, match *bot.Match, delay time.Duration) *BotViewer {
+	// Games read the style variables directly, so make sure they are set
+	styles.GetStyles()
+	useColorblind(match.Game)
+
+	return &BotViewer{game: game, match: match, delay: delay}
+}
+
+func (v *BotViewer) Init() tea.Cmd {
+	return tea.Batch(v.game.Init(), v.scheduleStep())
+}
+
+func (v *BotViewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.width = msg.Width
+		v.height = msg.Height
+		// Leave room for the status line
+		model, cmd := v.game.Update(tea.WindowSizeMsg{Width: msg.Width, Height: max(msg.Height-2, 0)})
+		v.game = model.(core.Game)
+		return v, cmd
+
+	case botStepMsg:
+		if msg.id != v.stepID || v.paused || v.thinking || v.match.Over() {
+			return v, nil
+		}
+		v.thinking = true
+		return v, func() tea.Msg {
+			return botTurnMsg{turn: v.match.Ask()}
+		}
+
+	case botTurnMsg:
+		v.thinking = false
+		v.match.Play(msg.turn)
+		if v.match.Over() {
+			v.match.Finish()
+			return v, nil
+		}
+		if v.paused {
+			return v, nil
+		}
+		return v, v.scheduleStep()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return v, tea.Quit
+		case " ":
+			v.paused = !v.paused
+			if !v.paused && !v.thinking {
+				return v, v.scheduleStep()
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// scheduleStep waits for the next tick of real-time games, or the delay
+// between turns
+func (v *BotViewer) scheduleStep() tea.Cmd {
+	interval := v.delay
+	if ticker, ok := v.game.(core.Ticker); ok && v.match.Realtime() {
+		if d := ticker.TickInterval(); d > 0 {
+			interval = d
+		}
+	}
+
+	v.stepID++
+	id := v.stepID
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return botStepMsg{id: id}
+	})
+}
+
+func (v *BotViewer) View() string {
+	var state string
+	switch {
+	case v.match.Over():
+		state = "■ " + v.match.Describe()
+	case v.paused:
+		state = "❚❚ Paused"
+	default:
+		state = "▶ Playing"
+	}
+
+	status := fmt.Sprintf("%s   turn %d", state, v.match.Turns())
+	for i, player := range v.match.Players() {
+		status += fmt.Sprintf("   %s: %s", v.match.PlayerName(i), player.Name)
+	}
+	help := "Space to pause, Q to quit"
+
+	statusLine := lipgloss.JoinHorizontal(lipgloss.Top,
+		styles.GetSelectedItemStyle().Render(status),
+		"   ",
+		styles.GetMenuItemStyle().Render(help),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Center,
+		v.game.View(),
+		"",
+		lipgloss.PlaceHorizontal(v.width, lipgloss.Center, statusLine),
+	)
+}
@@ -1,33 +1,100 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/config"
 	"github.com/jakmaz/arcade/internal/core"
+	snake "github.com/jakmaz/arcade/internal/games/snake/tui"
+	"github.com/jakmaz/arcade/internal/saves"
 	"github.com/jakmaz/arcade/internal/theme"
 	"github.com/jakmaz/arcade/internal/ui/styles"
 )
 
+// demoIdleSeconds is how long the menu waits for input before the
+// snake autopilot demo starts playing behind it
+const demoIdleSeconds = 30
+
+// Entries listed below the games
+const (
+	continueEntry     = "Continue"
+	statsEntry        = "Stats"
+	achievementsEntry = "Achievements"
+)
+
+// screen is a page opened from the menu. Update returns nil once the
+// screen is closed.
+type screen interface {
+	Update(msg tea.Msg) (screen, tea.Cmd)
+	View(width, height int) string
+}
+
 type model struct {
-	cursor int
-	games  []core.GameInfo
-	width  int
-	height int
+	cursor  int
+	games   []core.GameInfo
+	entries []string
+	save    *saves.File // the most recent saved game, if any
+	screen  screen
+	width   int
+	height  int
+
+	// Attract mode
+	idle   int
+	idleID int
+	demo   *snake.Model
+}
+
+// idleTickMsg counts the seconds the menu has been left alone
+type idleTickMsg struct {
+	id int
+}
+
+func idleTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return idleTickMsg{id: id}
+	})
+}
+
+// demoTickMsg moves the demo snake
+type demoTickMsg struct {
+	id int
+}
+
+func (m model) demoTick() tea.Cmd {
+	id := m.idleID
+	return tea.Tick(m.demo.TickInterval(), func(time.Time) tea.Msg {
+		return demoTickMsg{id: id}
+	})
 }
 
 func NewMenu() model {
 	theme.Initialize()
 
-	return model{
-		games: core.AvailableGames(),
+	m := model{games: core.AvailableGames()}
+	m.refreshEntries()
+	return m
+}
+
+// refreshEntries lists the entries below the games, offering to continue
+// the most recently saved game
+func (m *model) refreshEntries() {
+	m.save = nil
+	m.entries = []string{statsEntry, achievementsEntry}
+
+	if list, err := saves.List(); err == nil && len(list) > 0 {
+		m.save = &list[0]
+		m.entries = append([]string{continueEntry}, m.entries...)
 	}
+	m.cursor = min(m.cursor, m.itemCount()-1)
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	return idleTick(m.idleID)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -35,19 +102,58 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.demo != nil {
+			m.demo.Update(msg)
+		}
+	case idleTickMsg:
+		if msg.id != m.idleID {
+			return m, nil
+		}
+		m.idle++
+		if m.demo == nil && m.screen == nil && m.idle >= demoIdleSeconds {
+			m.demo = snake.NewDemo()
+			m.demo.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			return m, tea.Batch(idleTick(m.idleID), m.demo.Init(), m.demoTick())
+		}
+		return m, idleTick(m.idleID)
+	case demoTickMsg:
+		if msg.id != m.idleID || m.demo == nil {
+			return m, nil
+		}
+		m.demo.Update(core.TickMsg{})
+		return m, m.demoTick()
+	case ReturnToMenuMsg:
+		// Games swallow the menu's ticks, so start counting afresh
+		m.idleID++
+		m.idle = 0
+		m.demo = nil
+		m.refreshEntries()
+		return m, idleTick(m.idleID)
 	case tea.KeyMsg:
+		m.idle = 0
+		if m.demo != nil {
+			// The first key press only dismisses the demo
+			m.demo = nil
+			return m, nil
+		}
+		if m.screen != nil {
+			var cmd tea.Cmd
+			m.screen, cmd = m.screen.Update(msg)
+			return m, cmd
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
 
 		case "up", "k":
 			if m.cursor == 0 {
-				m.cursor = len(m.games) - 1
+				m.cursor = m.itemCount() - 1
 			} else {
 				m.cursor--
 			}
 		case "down", "j":
-			if m.cursor == len(m.games)-1 {
+			if m.cursor == m.itemCount()-1 {
 				m.cursor = 0
 			} else {
 				m.cursor++
@@ -56,12 +162,61 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.cycleToPreviousTheme()
 		case "right", "l":
 			return m, m.cycleToNextTheme()
+		case "c":
+			// Failing to save it only means the mode applies to this
+			// session
+			on := !styles.Colorblind()
+			styles.SetColorblind(on)
+			config.Update(func(c *config.Config) { c.Colorblind = &on })
+		case "o":
+			if m.cursor < len(m.games) {
+				m.screen = newOptionsScreen(m.games[m.cursor])
+			}
 		case "enter":
+			if m.cursor >= len(m.games) {
+				return m.openEntry(m.entries[m.cursor-len(m.games)])
+			}
 			selected := m.games[m.cursor] // Direct access to GameInfo
 			return m, func() tea.Msg {
 				return StartGameMsg{GameID: selected.ID}
 			}
 		}
+	default:
+		if m.demo != nil {
+			_, cmd := m.demo.Update(msg)
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+// itemCount is the number of selectable menu lines
+func (m model) itemCount() int {
+	return len(m.games) + len(m.entries)
+}
+
+func (m model) entryLabel(entry string) string {
+	if entry == continueEntry {
+		name := m.save.Game
+		if game, ok := core.Games[name]; ok {
+			name = game.Name
+		}
+		return fmt.Sprintf("%s — %s (saved %s)", entry, name, m.save.Saved.Local().Format("Jan 2 15:04"))
+	}
+	return entry
+}
+
+func (m model) openEntry(entry string) (tea.Model, tea.Cmd) {
+	switch entry {
+	case continueEntry:
+		gameID := m.save.Game
+		return m, func() tea.Msg {
+			return StartGameMsg{GameID: gameID, Resume: true}
+		}
+	case statsEntry:
+		m.screen = newStatsScreen(m.games)
+	case achievementsEntry:
+		m.screen = newAchievementsScreen()
 	}
 	return m, nil
 }
@@ -84,8 +239,10 @@ func (m model) cycleToPreviousTheme() tea.Cmd {
 		prevIndex := (currentIndex - 1 + len(availableThemes)) % len(availableThemes)
 		newTheme := availableThemes[prevIndex]
 
-		// Set the new theme
+		// Set the new theme and keep it for next time. Failing to save
+		// it only means the theme applies to this session.
 		theme.SetCurrentTheme(newTheme)
+		config.Update(func(c *config.Config) { c.Theme = newTheme })
 
 		return ThemeChangedMsg{ThemeName: newTheme}
 	}
@@ -109,8 +266,10 @@ func (m model) cycleToNextTheme() tea.Cmd {
 		nextIndex := (currentIndex + 1) % len(availableThemes)
 		newTheme := availableThemes[nextIndex]
 
-		// Set the new theme
+		// Set the new theme and keep it for next time. Failing to save
+		// it only means the theme applies to this session.
 		theme.SetCurrentTheme(newTheme)
+		config.Update(func(c *config.Config) { c.Theme = newTheme })
 
 		return ThemeChangedMsg{ThemeName: newTheme}
 	}
@@ -119,9 +278,36 @@ func (m model) cycleToNextTheme() tea.Cmd {
 // ThemeChangedMsg indicates a theme was changed
 type ThemeChangedMsg struct {
 	ThemeName string
+	Err       error // themes that failed to reload, if any
+}
+
+// WatchThemes reloads the themes whenever the user's theme files change
+// and repaints p with them. Call stop once p is done.
+func WatchThemes(p *tea.Program) (stop func()) {
+	return theme.Watch(theme.WatchInterval, func(errs []error) {
+		msg := ThemeChangedMsg{Err: errors.Join(errs...)}
+		if current := theme.GetCurrentTheme(); current != nil {
+			msg.ThemeName = current.Name()
+		}
+		p.Send(msg)
+	})
+}
+
+// themeChanged repaints with the current theme, telling the player about
+// themes that failed to reload
+func (t *toasts) themeChanged(msg ThemeChangedMsg) tea.Cmd {
+	styles.RefreshStyles()
+	if msg.Err == nil {
+		return nil
+	}
+	return t.push("Theme not reloaded", msg.Err.Error(), true)
 }
 
 func (m model) View() string {
+	if m.screen != nil {
+		return m.screen.View(m.width, m.height)
+	}
+
 	asciiArt := "                             _      \n" +
 		"     /\\                     | |     \n" +
 		"    /  \\   _ __ ___ __ _  __| | ___ \n" +
@@ -146,11 +332,26 @@ func (m model) View() string {
 	// Add separator (just UI)
 	items = append(items, styles.GetMenuItemStyle().Render("──────────────────────────────────────"))
 
+	for i, entry := range m.entries {
+		style := styles.GetMenuItemStyle()
+		cursor := " "
+		if m.cursor == len(m.games)+i {
+			style = styles.GetSelectedItemStyle()
+			cursor = "> "
+		}
+		items = append(items, style.Render(cursor+m.entryLabel(entry)))
+	}
+
 	// Add theme display (just UI)
 	currentTheme := theme.GetCurrentTheme()
 	themeDisplay := fmt.Sprintf(" Theme: ← %s → ", currentTheme.Name())
 	items = append(items, styles.GetMenuItemStyle().Render(themeDisplay))
-	help := styles.GetHelpStyle().Render("↑/↓ to move, ←/→ to change theme, Enter to select, q to quit")
+	colorblind := "off"
+	if styles.Colorblind() {
+		colorblind = "on"
+	}
+	items = append(items, styles.GetMenuItemStyle().Render(" Colorblind mode: "+colorblind+" "))
+	help := styles.GetHelpStyle().Render("↑/↓ to move, ←/→ to change theme, o for game options, c for colorblind mode, Enter to select, q to quit")
 
 	// Center everything
 	content := lipgloss.JoinVertical(lipgloss.Center,
@@ -159,5 +360,15 @@ func (m model) View() string {
 		help,
 	)
 
+	if m.demo != nil {
+		// A compact menu floating over the demo leaves room to watch it
+		box := overlayBox(lipgloss.JoinVertical(lipgloss.Center,
+			styles.GetTitleStyle().Render("Arcade"),
+			strings.Join(items[:len(m.games)], "\n"),
+			styles.GetHelpStyle().Render("Press any key to continue"),
+		))
+		return PlaceOverlayCenter(box, m.demo.View())
+	}
+
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
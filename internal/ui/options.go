@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/config"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// optionsScreen sets a game's default options, opened from the menu. The
+// options are kept in the config file, and apply whenever the game is
+// started without them.
+type optionsScreen struct {
+	game   core.GameInfo
+	values map[string]string // chosen values, empty for the game's default
+	cursor int
+	err    error
+}
+
+func newOptionsScreen(game core.GameInfo) *optionsScreen {
+	s := &optionsScreen{game: game, values: map[string]string{}}
+	for key, value := range config.GameOptions(game.ID) {
+		if game.CheckOption(key, value) == nil {
+			s.values[key] = value
+		}
+	}
+	return s
+}
+
+func (s *optionsScreen) Update(msg tea.Msg) (screen, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return s, nil
+	}
+	switch key.String() {
+	case "esc", "q", "enter":
+		return nil, nil
+	}
+	if len(s.game.Options) == 0 {
+		return s, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		s.cursor = (s.cursor - 1 + len(s.game.Options)) % len(s.game.Options)
+	case "down", "j":
+		s.cursor = (s.cursor + 1) % len(s.game.Options)
+	case "left", "h":
+		s.cycle(-1)
+	case "right", "l":
+		s.cycle(1)
+	}
+	return s, nil
+}
+
+// cycle steps the selected option through its values and the game's
+// default, and saves it
+func (s *optionsScreen) cycle(step int) {
+	option := s.game.Options[s.cursor]
+	if len(option.Values) == 0 {
+		return
+	}
+	// The game's default comes before the values
+	choices := append([]string{""}, option.Values...)
+	i := slices.Index(choices, s.values[option.Key])
+	value := choices[(i+step+len(choices))%len(choices)]
+
+	s.values[option.Key] = value
+	s.err = config.Update(func(c *config.Config) {
+		c.SetGameOption(s.game.ID, option.Key, value)
+	})
+}
+
+func (s *optionsScreen) View(width, height int) string {
+	var lines []string
+	for i, option := range s.game.Options {
+		value := s.values[option.Key]
+		switch {
+		case value != "":
+		case option.Default != "":
+			value = "default (" + option.Default + ")"
+		default:
+			value = "default"
+		}
+
+		style := styles.GetMenuItemStyle()
+		cursor := "  "
+		if i == s.cursor {
+			style = styles.GetSelectedItemStyle()
+			cursor = "> "
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%s%-10s ← %s →", cursor, option.Key, value)))
+		if i == s.cursor && option.Help != "" {
+			lines = append(lines, styles.GetMenuItemStyle().Faint(true).Render("    "+option.Help))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, styles.GetMenuItemStyle().Render("No options"))
+	}
+	if s.err != nil {
+		lines = append(lines, "", styles.GetErrorStyle().Render(s.err.Error()))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		styles.GetTitleStyle().Render(s.game.Name+" options"),
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+		styles.GetHelpStyle().Render("↑/↓ to move, ←/→ to change, ESC to go back"),
+	)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// PlaceOverlay draws fg on top of bg with its top-left corner at x, y.
+// Both strings may contain ANSI styling.
+func PlaceOverlay(x, y int, fg, bg string) string {
+	fgLines := strings.Split(fg, "\n")
+	bgLines := strings.Split(bg, "\n")
+
+	for i, fgLine := range fgLines {
+		row := y + i
+		if row < 0 || row >= len(bgLines) {
+			continue
+		}
+
+		bgLine := bgLines[row]
+		left := ansi.Truncate(bgLine, x, "")
+		if pad := x - ansi.StringWidth(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		right := ansi.TruncateLeft(bgLine, x+ansi.StringWidth(fgLine), "")
+
+		bgLines[row] = left + ansi.ResetStyle + fgLine + ansi.ResetStyle + right
+	}
+
+	return strings.Join(bgLines, "\n")
+}
+
+// PlaceOverlayCenter draws fg in the middle of bg
+func PlaceOverlayCenter(fg, bg string) string {
+	x := (lipgloss.Width(bg) - lipgloss.Width(fg)) / 2
+	y := (lipgloss.Height(bg) - lipgloss.Height(fg)) / 2
+	return PlaceOverlay(max(x, 0), max(y, 0), fg, bg)
+}
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/replay"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+const (
+	// replayKeyInterval paces key presses of games that don't tick
+	replayKeyInterval = 500 * time.Millisecond
+
+	minReplaySpeed = 0.125
+	maxReplaySpeed = 16
+)
+
+// ReplayPlayer plays a recorded game back by feeding it the recorded
+// keys and ticks in order
+type ReplayPlayer struct {
+	replay        *replay.Replay
+	game          core.Game
+	next          int // index of the next key press
+	ticks         int // ticks delivered so far
+	speed         float64
+	paused        bool
+	stepID        int
+	width, height int
+}
+
+// replayStepMsg is due when the next step of the replay is
+type replayStepMsg struct {
+	id int
+}
+
+// NewReplayPlayer recreates the recorded game, ready to play back at
+// the given speed
+func NewReplayPlayer(r *replay.Replay, speed float64) (*ReplayPlayer, error) {
+	// Games read the style variables directly, so make sure they are set
+	styles.GetStyles()
+	useColorblind(r.Game)
+
+	game, err := core.CreateGame(r.Game, r.Options)
+	if err != nil {
+		return nil, err
+	}
+	if r.State != nil {
+		saveable, ok := game.(core.Saveable)
+		if !ok {
+			return nil, fmt.Errorf("%s games can't be resumed", r.Game)
+		}
+		if err := saveable.LoadState(r.State); err != nil {
+			return nil, fmt.Errorf("failed to restore the replay's starting point: %w", err)
+		}
+	}
+
+	return &ReplayPlayer{
+		replay: r,
+		game:   game,
+		speed:  min(max(speed, minReplaySpeed), maxReplaySpeed),
+	}, nil
+}
+
+func (p *ReplayPlayer) Init() tea.Cmd {
+	return tea.Batch(p.game.Init(), p.scheduleStep())
+}
+
+func (p *ReplayPlayer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width = msg.Width
+		p.height = msg.Height
+		// Leave room for the status line
+		return p, p.updateGame(tea.WindowSizeMsg{Width: msg.Width, Height: max(msg.Height-2, 0)})
+
+	case replayStepMsg:
+		if msg.id != p.stepID || p.paused || p.done() {
+			return p, nil
+		}
+		return p, tea.Batch(p.step(), p.scheduleStep())
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return p, tea.Quit
+		case " ":
+			p.paused = !p.paused
+			if !p.paused {
+				return p, p.scheduleStep()
+			}
+		case "right", ".":
+			if p.paused && !p.done() {
+				return p, p.step()
+			}
+		case "+", "=":
+			p.speed = min(p.speed*2, maxReplaySpeed)
+		case "-":
+			p.speed = max(p.speed/2, minReplaySpeed)
+		}
+		return p, nil
+	}
+
+	return p, p.updateGame(msg)
+}
+
+func (p *ReplayPlayer) updateGame(msg tea.Msg) tea.Cmd {
+	model, cmd := p.game.Update(msg)
+	p.game = model.(core.Game)
+	return cmd
+}
+
+func (p *ReplayPlayer) done() bool {
+	return p.ticks >= p.replay.Ticks && p.next >= len(p.replay.Events)
+}
+
+// step replays the keys pressed before the next tick and then the tick.
+// Once every tick is delivered, the remaining keys are replayed one per
+// step.
+func (p *ReplayPlayer) step() tea.Cmd {
+	var cmds []tea.Cmd
+	events := p.replay.Events
+
+	if p.ticks < p.replay.Ticks {
+		for p.next < len(events) && events[p.next].Tick <= p.ticks {
+			cmds = append(cmds, p.play(events[p.next]))
+			p.next++
+		}
+		cmds = append(cmds, p.updateGame(core.TickMsg{}))
+		p.ticks++
+	} else if p.next < len(events) {
+		cmds = append(cmds, p.play(events[p.next]))
+		p.next++
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// play delivers a recorded key to the game, or plays a bot's action on
+// its engine
+func (p *ReplayPlayer) play(event replay.Event) tea.Cmd {
+	if event.Action == "" {
+		return p.updateGame(replay.KeyMsg(event.Key))
+	}
+	if driven, ok := p.game.(core.Driven); ok && driven.Engine() != nil {
+		driven.Engine().Step(event.Action)
+	}
+	return nil
+}
+
+// scheduleStep waits for the next step at the game's own pace, scaled by
+// the playback speed
+func (p *ReplayPlayer) scheduleStep() tea.Cmd {
+	interval := replayKeyInterval
+	if ticker, ok := p.game.(core.Ticker); ok && p.ticks < p.replay.Ticks {
+		if d := ticker.TickInterval(); d > 0 {
+			interval = d
+		}
+	}
+
+	p.stepID++
+	id := p.stepID
+	return tea.Tick(time.Duration(float64(interval)/p.speed), func(time.Time) tea.Msg {
+		return replayStepMsg{id: id}
+	})
+}
+
+func (p *ReplayPlayer) View() string {
+	var state string
+	switch {
+	case p.done():
+		state = "■ Finished"
+	case p.paused:
+		state = "❚❚ Paused"
+	default:
+		state = "▶ Playing"
+	}
+
+	status := fmt.Sprintf("%s  %gx   tick %d/%d   key %d/%d",
+		state, p.speed, p.ticks, p.replay.Ticks, p.next, len(p.replay.Events))
+	help := "Space to pause, → to step, +/- to change speed, Q to quit"
+
+	statusLine := lipgloss.JoinHorizontal(lipgloss.Top,
+		styles.GetSelectedItemStyle().Render(status),
+		"   ",
+		styles.GetMenuItemStyle().Render(help),
+	)
+
+	return lipgloss.JoinVertical(lipgloss.Center,
+		p.game.View(),
+		"",
+		lipgloss.PlaceHorizontal(p.width, lipgloss.Center, statusLine),
+	)
+}
@@ -0,0 +1,558 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/achievements"
+	"github.com/jakmaz/arcade/internal/config"
+	"github.com/jakmaz/arcade/internal/core"
+	_ "github.com/jakmaz/arcade/internal/games"
+	"github.com/jakmaz/arcade/internal/replay"
+	"github.com/jakmaz/arcade/internal/saves"
+	"github.com/jakmaz/arcade/internal/scores"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// Session runs one game for a host, either the menu app or a direct
+// launch. It owns the controls every game shares - pausing and playing
+// again - and draws the pause and game-over overlays over the game.
+// Finished games are recorded in the score file and as a replay, and
+// unlocked achievements are announced with toasts.
+//
+// The session also sends the game its ticks, so that it can count them
+// for the replay and hold them back while the game is paused.
+type Session struct {
+	gameID        string
+	options       core.Options // as chosen by the player, without the seed
+	seed          string
+	game          core.Game
+	width, height int
+	tickID        int
+	recording     *replay.Replay
+
+	started   time.Time // when play began, zero before a mode is chosen
+	pausedAt  time.Time
+	pausedFor time.Duration
+	previous  time.Duration   // play time before the game was resumed
+	record    *scores.Record  // the finished game, once recorded
+	history   []scores.Record // every recorded game, loaded at game over
+	scoresErr error
+	saving    bool // the finished game is being added to the scores
+
+	replayPath string
+	replayErr  error
+
+	ephemeral bool // leave no records behind, see NewEphemeralSession
+	toasts    toasts
+}
+
+// leaderboardSize is how many entries the game-over screen lists
+const leaderboardSize = 10
+
+// sessionTickMsg is due when the game's next tick is. The ID ties it to
+// one run of the game, so ticks scheduled before a restart are dropped.
+type sessionTickMsg struct {
+	id int
+}
+
+// scoresSavedMsg reports the finished game of one run added to the
+// scores, with every recorded game loaded afterwards
+type scoresSavedMsg struct {
+	id      int
+	history []scores.Record
+	err     error
+}
+
+// achievementsSavedMsg reports saving the achievements progress
+type achievementsSavedMsg struct {
+	err error
+}
+
+// lastTickID hands out tick IDs, unique across sessions
+var lastTickID int
+
+// NewSession creates the game and wraps it in a session. Options not
+// given fall back to the game's options in the config file. Games are
+// seeded randomly unless options sets core.SeedOption.
+func NewSession(gameID string, options core.Options) (*Session, error) {
+	achievements.Start()
+	return newSession(gameID, withDefaults(gameID, options), false)
+}
+
+// withDefaults adds the game's options from the config file that options
+// don't set. Options the game doesn't accept are left out.
+func withDefaults(gameID string, options core.Options) core.Options {
+	merged := core.Options{}
+	if info, ok := core.Games[gameID]; ok {
+		for key, value := range config.GameOptions(gameID) {
+			if info.CheckOption(key, value) == nil {
+				merged[key] = value
+			}
+		}
+	}
+	for key, value := range options {
+		merged[key] = value
+	}
+	return merged
+}
+
+// NewEphemeralSession creates a session that leaves no trace, for games
+// embedded in other programs: finished games are not added to the
+// scores or recorded as replays, and achievements are not tracked.
+func NewEphemeralSession(gameID string, options core.Options) (*Session, error) {
+	return newSession(gameID, options, true)
+}
+
+// useColorblind turns colorblind mode on or off as the config file sets
+// it for gameID, or for the menu when gameID is empty
+func useColorblind(gameID string) {
+	styles.SetColorblind(config.Colorblind(gameID))
+}
+
+func newSession(gameID string, options core.Options, ephemeral bool) (*Session, error) {
+	// Games read the style variables directly, so make sure they are set
+	styles.GetStyles()
+	if !ephemeral {
+		useColorblind(gameID)
+	}
+
+	s := &Session{
+		gameID:    gameID,
+		options:   core.Options{},
+		seed:      options.Get(core.SeedOption, core.NewSeed()),
+		ephemeral: ephemeral,
+	}
+	for key, value := range options {
+		if key != core.SeedOption {
+			s.options[key] = value
+		}
+	}
+
+	game, err := core.CreateGame(gameID, s.gameOptions())
+	if err != nil {
+		return nil, err
+	}
+	s.game = game
+	s.startRecording()
+	return s, nil
+}
+
+// gameOptions are the options the game is created with
+func (s *Session) gameOptions() core.Options {
+	options := core.Options{core.SeedOption: s.seed}
+	for key, value := range s.options {
+		options[key] = value
+	}
+	return options
+}
+
+func (s *Session) startRecording() {
+	lastTickID++
+	s.tickID = lastTickID
+	s.recording = replay.New(s.gameID, s.gameOptions())
+}
+
+// ResumeSession continues the saved game of gameID. The game starts
+// paused, so the player can get ready.
+func ResumeSession(gameID string) (*Session, error) {
+	save, err := saves.Read(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The saved options are complete, so the config file has no say
+	achievements.Start()
+	s, err := newSession(gameID, save.Options, false)
+	if err != nil {
+		return nil, err
+	}
+
+	saveable, ok := s.game.(core.Saveable)
+	if !ok {
+		return nil, fmt.Errorf("%s games can't be resumed", gameID)
+	}
+	if err := saveable.LoadState(save.State); err != nil {
+		return nil, fmt.Errorf("failed to resume %s: %w", gameID, err)
+	}
+	s.recording.State = save.State
+
+	s.previous = save.Elapsed
+	s.started = time.Now()
+	s.game.Pause()
+	s.pausedAt = s.started
+	return s, nil
+}
+
+// Save writes the game in progress to its save file, to be resumed
+// later. Games that are finished, or not started yet, are not saved.
+func (s *Session) Save() error {
+	saveable, ok := s.game.(core.Saveable)
+	state := s.game.State()
+	if !ok || state.Mode == "" || state.Over {
+		return nil
+	}
+
+	data, err := saveable.SaveState()
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", s.gameID, err)
+	}
+
+	return saves.Write(saves.File{
+		Game:    s.gameID,
+		Options: s.gameOptions(),
+		Saved:   time.Now(),
+		Elapsed: s.Elapsed(),
+		State:   data,
+	})
+}
+
+// Elapsed is the time spent playing, without pauses
+func (s *Session) Elapsed() time.Duration {
+	if s.started.IsZero() {
+		return s.previous
+	}
+	played := time.Since(s.started) - s.pausedFor
+	if s.game.State().Paused {
+		played -= time.Since(s.pausedAt)
+	}
+	return s.previous + played
+}
+
+// Pause freezes the game and its play clock
+func (s *Session) Pause() {
+	if s.game.State().Paused {
+		return
+	}
+	s.game.Pause()
+	s.pausedAt = time.Now()
+}
+
+func (s *Session) Resume() {
+	if !s.game.State().Paused {
+		return
+	}
+	s.game.Resume()
+	s.pausedFor += time.Since(s.pausedAt)
+}
+
+// Game returns the running game
+func (s *Session) Game() core.Game {
+	return s.game
+}
+
+func (s *Session) Init() tea.Cmd {
+	return tea.Batch(s.game.Init(), s.scheduleTick())
+}
+
+// scheduleTick waits for the game's next tick, if it wants one
+func (s *Session) scheduleTick() tea.Cmd {
+	ticker, ok := s.game.(core.Ticker)
+	if !ok {
+		return nil
+	}
+	interval := ticker.TickInterval()
+	if interval <= 0 {
+		return nil
+	}
+
+	id := s.tickID
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return sessionTickMsg{id: id}
+	})
+}
+
+func (s *Session) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.width = msg.Width
+		s.height = msg.Height
+
+	case toastExpiredMsg:
+		if s.toasts.expire(msg) {
+			return nil
+		}
+
+	case ThemeChangedMsg:
+		return s.toasts.themeChanged(msg)
+
+	case scoresSavedMsg:
+		if msg.id == s.tickID {
+			s.saving = false
+			s.history, s.scoresErr = msg.history, msg.err
+		}
+		return nil
+
+	case achievementsSavedMsg:
+		if msg.err != nil {
+			return s.toasts.push("Achievements not saved", msg.err.Error(), true)
+		}
+		return nil
+
+	case sessionTickMsg:
+		if msg.id != s.tickID {
+			return nil
+		}
+		state := s.game.State()
+		if state.Over {
+			return nil
+		}
+		if state.Paused {
+			// Keep the clock running without moving the game
+			return s.scheduleTick()
+		}
+		s.recording.Tick()
+		return tea.Batch(s.updateGame(core.TickMsg{}), s.scheduleTick())
+
+	case tea.KeyMsg:
+		state := s.game.State()
+		switch {
+		case state.Over:
+			if msg.String() == "r" {
+				return s.restart()
+			}
+			return nil
+		case msg.String() == "p":
+			if state.Paused {
+				s.Resume()
+			} else {
+				s.Pause()
+			}
+			return nil
+		case state.Paused:
+			return nil
+		}
+		s.recording.Key(msg)
+	}
+
+	return s.updateGame(msg)
+}
+
+func (s *Session) updateGame(msg tea.Msg) tea.Cmd {
+	model, cmd := s.game.Update(msg)
+	s.game = model.(core.Game)
+	return tea.Batch(cmd, s.track(), s.announceAchievements())
+}
+
+// announceAchievements shows a toast for every achievement the game just
+// unlocked, and saves the progress the game made
+func (s *Session) announceAchievements() tea.Cmd {
+	unlocked, unsaved := achievements.TakeUnlocked()
+
+	var cmds []tea.Cmd
+	for _, a := range unlocked {
+		cmds = append(cmds, s.toasts.push("★ Achievement unlocked", a.Name+"\n"+a.Description, false))
+	}
+	if unsaved {
+		cmds = append(cmds, func() tea.Msg {
+			return achievementsSavedMsg{err: achievements.Save()}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// track starts the play clock once a mode is chosen and records the game
+// when it finishes
+func (s *Session) track() tea.Cmd {
+	state := s.game.State()
+	if s.started.IsZero() && state.Mode != "" {
+		s.started = time.Now()
+	}
+	if !state.Over || s.record != nil {
+		return nil
+	}
+	cmd := s.recordGame(state)
+	if !s.ephemeral {
+		// A finished game can't be continued, so drop its save
+		saves.Delete(s.gameID)
+	}
+	return cmd
+}
+
+// recordGame records the finished game as a replay and returns the
+// command adding it to the scores. Other arcade processes may hold the
+// score file, so the scores are saved off the UI goroutine.
+func (s *Session) recordGame(state core.State) tea.Cmd {
+	record := scores.Record{
+		Game:     s.gameID,
+		Mode:     state.Mode,
+		Options:  s.options,
+		Score:    state.Score,
+		Outcome:  state.Outcome.String(),
+		Details:  state.Details,
+		Duration: s.Elapsed().Round(time.Second),
+		Date:     time.Now(),
+		Player:   scores.Player(),
+	}
+	s.record = &record
+	if s.ephemeral {
+		return nil
+	}
+	s.replayPath, s.replayErr = replay.Write(s.recording)
+
+	// Games a bot helped with stay off the leaderboards
+	if state.Assisted {
+		return nil
+	}
+	s.saving = true
+	id := s.tickID
+	return func() tea.Msg {
+		if err := scores.Add(record); err != nil {
+			return scoresSavedMsg{id: id, err: err}
+		}
+		history, err := scores.Load()
+		return scoresSavedMsg{id: id, history: history, err: err}
+	}
+}
+
+// restart replaces the finished game with a fresh one using the same
+// options
+func (s *Session) restart() tea.Cmd {
+	s.seed = core.NewSeed()
+	game, err := core.CreateGame(s.gameID, s.gameOptions())
+	if err != nil {
+		return nil
+	}
+
+	s.game = game
+	s.startRecording()
+	s.started = time.Time{}
+	s.pausedFor = 0
+	s.previous = 0
+	s.record = nil
+	s.history = nil
+	s.scoresErr = nil
+	s.saving = false
+	s.replayPath = ""
+	s.replayErr = nil
+	sizeCmd := s.updateGame(tea.WindowSizeMsg{Width: s.width, Height: s.height})
+	return tea.Batch(sizeCmd, s.Init())
+}
+
+func (s *Session) View() string {
+	view := s.game.View()
+
+	state := s.game.State()
+	switch {
+	case state.Over:
+		view = PlaceOverlayCenter(s.renderGameOver(state), view)
+	case state.Paused:
+		view = PlaceOverlayCenter(s.renderPause(), view)
+	}
+	return s.toasts.render(view)
+}
+
+func (s *Session) renderPause() string {
+	var lines []string
+	for _, binding := range s.game.KeyBindings() {
+		lines = append(lines, fmt.Sprintf("%-10s %s", binding.Keys, binding.Help))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		styles.GetTitleStyle().Render("Paused"),
+		styles.GetMenuItemStyle().Render(strings.Join(lines, "\n")),
+		styles.GetHelpStyle().Render("P to resume, ESC to return to menu"),
+	)
+
+	return overlayBox(content)
+}
+
+func (s *Session) renderGameOver(state core.State) string {
+	var headline string
+	switch state.Outcome {
+	case core.OutcomeDraw:
+		headline = "Draw!"
+	case core.OutcomeWin, core.OutcomeLoss:
+		headline = state.Winner + " won!"
+	default:
+		headline = "Game Over"
+	}
+
+	sections := []string{styles.GetTitleStyle().Render(headline)}
+	if state.Outcome == core.OutcomeNone {
+		sections = append(sections, styles.GetSelectedItemStyle().Render(fmt.Sprintf("Score: %d", state.Score)))
+	}
+	if leaderboard := s.renderLeaderboard(state); leaderboard != "" {
+		sections = append(sections, leaderboard)
+	}
+	switch {
+	case s.replayErr != nil:
+		sections = append(sections, styles.GetErrorStyle().Render("Replay not saved: "+s.replayErr.Error()))
+	case s.replayPath != "":
+		sections = append(sections, styles.GetMenuItemStyle().Render("Replay saved as "+filepath.Base(s.replayPath)))
+	}
+	sections = append(sections, styles.GetHelpStyle().Render("R to play again, ESC to return to menu"))
+
+	return overlayBox(lipgloss.JoinVertical(lipgloss.Center, sections...))
+}
+
+// renderLeaderboard lists the best results of the finished game's mode:
+// the top scores for solo games, and the players with the most wins for
+// games with a winner
+func (s *Session) renderLeaderboard(state core.State) string {
+	if s.scoresErr != nil {
+		return styles.GetErrorStyle().Render("Score not saved: " + s.scoresErr.Error())
+	}
+	if s.record == nil || s.ephemeral {
+		return ""
+	}
+	if state.Assisted {
+		return styles.GetMenuItemStyle().Render("Assisted games are not scored")
+	}
+	if s.saving {
+		return styles.GetMenuItemStyle().Render("Saving score…")
+	}
+
+	var lines []string
+	if state.Outcome == core.OutcomeNone {
+		lines = append(lines, fmt.Sprintf("%-3s %-12s %7s  %s", "#", "Player", "Score", "Date"))
+		for i, r := range scores.Top(s.history, s.gameID, state.Mode, len(s.history)) {
+			current := r.Date.Equal(s.record.Date) && r.Player == s.record.Player
+			if i >= leaderboardSize && !current {
+				continue
+			}
+
+			line := fmt.Sprintf("%-3d %-12s %7d  %s", i+1, truncate(r.Player, 12), r.Score, r.Date.Format("2006-01-02"))
+			if current {
+				line = styles.GetSelectedItemStyle().Render(line)
+			}
+			lines = append(lines, line)
+		}
+	} else {
+		lines = append(lines, fmt.Sprintf("%-3s %-12s %4s %4s %4s", "#", "Player", "W", "L", "D"))
+		for i, st := range scores.Standings(s.history, s.gameID, state.Mode, leaderboardSize) {
+			line := fmt.Sprintf("%-3d %-12s %4d %4d %4d", i+1, truncate(st.Player, 12), st.Wins, st.Losses, st.Draws)
+			if st.Player == s.record.Player {
+				line = styles.GetSelectedItemStyle().Render(line)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		styles.GetMenuItemStyle().Render("Top "+fmt.Sprint(leaderboardSize)),
+		strings.Join(lines, "\n"),
+	)
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// overlayBox frames content drawn on top of a game
+func overlayBox(content string) string {
+	return styles.GetTerminalBackgroundStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.GetBorderStyle().GetForeground()).
+		Padding(0, 2).
+		Render(content)
+}
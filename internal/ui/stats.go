@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/scores"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// FormatStats describes a game's history as lines of plain text
+func FormatStats(summary scores.Summary) []string {
+	if summary.Played == 0 {
+		return []string{"No games played yet"}
+	}
+
+	games := "games"
+	if summary.Played == 1 {
+		games = "game"
+	}
+	lines := []string{
+		fmt.Sprintf("Played:  %d %s in %s", summary.Played, games, scores.FormatDuration(summary.PlayTime)),
+	}
+	if summary.Wins+summary.Losses+summary.Draws > 0 {
+		lines = append(lines, fmt.Sprintf("Results: %d W / %d L / %d D", summary.Wins, summary.Losses, summary.Draws))
+	}
+	if summary.Scored > 0 {
+		lines = append(lines,
+			fmt.Sprintf("Best:    %d", summary.Best),
+			fmt.Sprintf("Average: %.1f", summary.Average),
+			fmt.Sprintf("Recent:  %s", scores.Sparkline(summary.Recent)),
+		)
+	}
+	if summary.LinesPerMinute > 0 {
+		lines = append(lines, fmt.Sprintf("Lines:   %.1f per minute", summary.LinesPerMinute))
+	}
+	return lines
+}
+
+// statsScreen shows the totals of every game, opened from the menu
+type statsScreen struct {
+	games     []core.GameInfo
+	summaries []scores.Summary
+	err       error
+}
+
+func newStatsScreen(games []core.GameInfo) *statsScreen {
+	s := &statsScreen{games: games}
+
+	records, err := scores.Load()
+	if err != nil {
+		s.err = err
+		return s
+	}
+	for _, game := range games {
+		s.summaries = append(s.summaries, scores.Summarize(records, game.ID))
+	}
+	return s
+}
+
+func (s *statsScreen) Update(msg tea.Msg) (screen, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q", "enter":
+			return nil, nil
+		}
+	}
+	return s, nil
+}
+
+func (s *statsScreen) View(width, height int) string {
+	var body string
+	if s.err != nil {
+		body = styles.GetErrorStyle().Render(s.err.Error())
+	} else {
+		// Two games per row
+		var rows, row []string
+		for i, summary := range s.summaries {
+			block := lipgloss.JoinVertical(lipgloss.Left,
+				styles.GetSelectedItemStyle().Render(s.games[i].Name),
+				styles.GetMenuItemStyle().Render(lipgloss.JoinVertical(lipgloss.Left, FormatStats(summary)...)),
+			)
+			row = append(row, lipgloss.NewStyle().Width(38).Padding(0, 1, 1).Render(block))
+			if len(row) == 2 || i == len(s.summaries)-1 {
+				rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+				row = nil
+			}
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left, rows...)
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		styles.GetTitleStyle().Render("Stats"),
+		body,
+		styles.GetHelpStyle().Render("ESC to go back"),
+	)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -0,0 +1,46 @@
+package styles
+
+import "github.com/jakmaz/arcade/internal/theme"
+
+// colorblind is set while games are drawn in colorblind mode
+var colorblind bool
+
+// Colorblind reports whether colorblind mode is on
+func Colorblind() bool {
+	return colorblind
+}
+
+// SetColorblind turns colorblind mode on or off. In colorblind mode games
+// take colors that work with any color vision deficiency, see
+// theme.Colorblind, and tell pieces and players apart by their shape too.
+func SetColorblind(on bool) {
+	ensureInitialized()
+	if on != colorblind {
+		colorblind = on
+		RefreshStyles()
+	}
+}
+
+// tetrisPatterns fill the blocks of each tetris piece in colorblind mode
+var tetrisPatterns = map[string]string{
+	"I": "██", "O": "▓▓", "T": "▒▒", "S": "▚▚", "Z": "▞▞", "J": "░░", "L": "▄▄",
+}
+
+// TetrisBlock returns the two cells drawn for a block of a tetris piece:
+// solid, or in colorblind mode the piece's pattern
+func TetrisBlock(piece string) string {
+	if pattern, ok := tetrisPatterns[piece]; ok && colorblind {
+		return pattern
+	}
+	return "██"
+}
+
+// newStyles makes the styles of the current theme, as colorblind mode
+// draws it when it's on
+func newStyles() *theme.Styles {
+	t := theme.GetCurrentTheme()
+	if colorblind && t != nil {
+		t = theme.Colorblind(t)
+	}
+	return theme.NewStylesWithTheme(t)
+}
@@ -14,6 +14,9 @@ var (
 	Secondary = lipgloss.AdaptiveColor{Light: "#585858", Dark: "#a8a8a8"}
 	Accent    = lipgloss.AdaptiveColor{Light: "#0066cc", Dark: "#66b3ff"}
 	Success   = lipgloss.AdaptiveColor{Light: "#22c55e", Dark: "#4ade80"}
+
+	// ChessDarkSquare is the background of the dark squares of the chess board
+	ChessDarkSquare = theme.ChessDarkSquare
 )
 
 // Lazy initialization variables
@@ -31,6 +34,11 @@ var (
 	BlackPieceStyle         lipgloss.Style
 	SnakeStyle              lipgloss.Style
 	SnakeHeadStyle          lipgloss.Style
+	FoodStyle               lipgloss.Style
+	WarningStyle            lipgloss.Style
+	ErrorStyle              lipgloss.Style
+	Player1Style            lipgloss.Style
+	Player2Style            lipgloss.Style
 	TerminalBackgroundStyle lipgloss.Style
 )
 
@@ -39,7 +47,7 @@ func ensureInitialized() {
 	if styles == nil {
 		// Initialize themes first
 		theme.Initialize()
-		styles = theme.NewStyles()
+		styles = newStyles()
 
 		// Set all style variables
 		TitleStyle = styles.TitleStyle()
@@ -57,6 +65,11 @@ func ensureInitialized() {
 		BlackPieceStyle = styles.BlackPieceStyle()
 		SnakeStyle = styles.SnakeStyle()
 		SnakeHeadStyle = styles.SnakeHeadStyle()
+		FoodStyle = styles.FoodStyle()
+		WarningStyle = styles.WarningStyle()
+		ErrorStyle = styles.ErrorStyle()
+		Player1Style = styles.Player1Style()
+		Player2Style = styles.Player2Style()
 		TerminalBackgroundStyle = styles.TerminalBackgroundStyle()
 	}
 }
@@ -139,6 +152,36 @@ func GetSnakeHeadStyle() lipgloss.Style {
 	return SnakeHeadStyle
 }
 
+// GetFoodStyle returns the food style, initializing if needed
+func GetFoodStyle() lipgloss.Style {
+	ensureInitialized()
+	return FoodStyle
+}
+
+// GetWarningStyle returns the warning style, initializing if needed
+func GetWarningStyle() lipgloss.Style {
+	ensureInitialized()
+	return WarningStyle
+}
+
+// GetErrorStyle returns the error style, initializing if needed
+func GetErrorStyle() lipgloss.Style {
+	ensureInitialized()
+	return ErrorStyle
+}
+
+// GetPlayer1Style returns the first player style, initializing if needed
+func GetPlayer1Style() lipgloss.Style {
+	ensureInitialized()
+	return Player1Style
+}
+
+// GetPlayer2Style returns the second player style, initializing if needed
+func GetPlayer2Style() lipgloss.Style {
+	ensureInitialized()
+	return Player2Style
+}
+
 // GetTerminalBackgroundStyle returns the terminal background style, initializing if needed
 func GetTerminalBackgroundStyle() lipgloss.Style {
 	ensureInitialized()
@@ -147,7 +190,7 @@ func GetTerminalBackgroundStyle() lipgloss.Style {
 
 // RefreshStyles updates all styles with the current theme
 func RefreshStyles() {
-	styles = theme.NewStyles()
+	styles = newStyles()
 
 	// Update all style variables
 	TitleStyle = styles.TitleStyle()
@@ -165,6 +208,11 @@ func RefreshStyles() {
 	BlackPieceStyle = styles.BlackPieceStyle()
 	SnakeStyle = styles.SnakeStyle()
 	SnakeHeadStyle = styles.SnakeHeadStyle()
+	FoodStyle = styles.FoodStyle()
+	WarningStyle = styles.WarningStyle()
+	ErrorStyle = styles.ErrorStyle()
+	Player1Style = styles.Player1Style()
+	Player2Style = styles.Player2Style()
 	TerminalBackgroundStyle = styles.TerminalBackgroundStyle()
 }
 
@@ -0,0 +1,330 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/theme"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// editorStep is what the theme editor is asking for
+type editorStep int
+
+const (
+	pickBaseStep editorStep = iota
+	editColorsStep
+	nameThemeStep
+)
+
+// ThemeEditor makes a new theme from an existing one. The player picks the
+// theme to start from and changes any of its colors while a preview shows
+// the result. The theme is saved with the changed colors, extending the
+// one it started from.
+type ThemeEditor struct {
+	step    editorStep
+	themes  []string
+	cursor  int // in themes, or in theme.Roles once editing
+	def     theme.ThemeDefinition
+	preview theme.Theme
+
+	editing bool   // typing a color
+	input   string // the color or name being typed
+	before  string // the role's setting before editing it
+	message string // error or hint under the colors
+	confirm bool   // asked to press the key again, to quit or overwrite
+
+	savedName, savedPath string
+	width, height        int
+}
+
+// NewThemeEditor starts the editor on the current theme
+func NewThemeEditor() *ThemeEditor {
+	e := &ThemeEditor{themes: theme.ListThemes()}
+	if current := theme.GetCurrentTheme(); current != nil {
+		for i, name := range e.themes {
+			if name == current.Name() {
+				e.cursor = i
+			}
+		}
+	}
+	return e
+}
+
+// Saved returns the theme the editor saved and its file, empty if the
+// player quit without saving
+func (e *ThemeEditor) Saved() (name, path string) {
+	return e.savedName, e.savedPath
+}
+
+func (e *ThemeEditor) Init() tea.Cmd {
+	return nil
+}
+
+func (e *ThemeEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.width = msg.Width
+		e.height = msg.Height
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return e, tea.Quit
+		}
+		switch e.step {
+		case pickBaseStep:
+			return e, e.updatePickBase(msg)
+		case editColorsStep:
+			if e.editing {
+				e.updateColorInput(msg)
+				return e, nil
+			}
+			return e, e.updateEditColors(msg)
+		case nameThemeStep:
+			return e, e.updateName(msg)
+		}
+	}
+	return e, nil
+}
+
+func (e *ThemeEditor) updatePickBase(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		e.cursor = (e.cursor - 1 + len(e.themes)) % len(e.themes)
+	case "down", "j":
+		e.cursor = (e.cursor + 1) % len(e.themes)
+	case "enter", " ":
+		base := e.themes[e.cursor]
+		e.def = theme.ThemeDefinition{Name: base + "-custom", Extends: base}
+		e.step = editColorsStep
+		e.cursor = 0
+		e.rebuild()
+	case "esc", "q":
+		return tea.Quit
+	}
+	return nil
+}
+
+func (e *ThemeEditor) updateEditColors(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	if key != "q" && key != "esc" {
+		e.confirm = false
+		e.message = ""
+	}
+
+	role := theme.Roles[e.cursor]
+	switch key {
+	case "up", "k":
+		e.cursor = (e.cursor - 1 + len(theme.Roles)) % len(theme.Roles)
+	case "down", "j":
+		e.cursor = (e.cursor + 1) % len(theme.Roles)
+	case "enter", " ":
+		e.editing = true
+		e.before = role.Setting(&e.def)
+		e.input = theme.ColorString(role.Color(e.preview))
+		if !theme.IsHexColor(e.input) {
+			e.input = "#"
+		}
+	case "x", "backspace", "delete":
+		role.Set(&e.def, "")
+		e.rebuild()
+	case "s":
+		e.step = nameThemeStep
+		e.input = e.def.Name
+	case "q", "esc":
+		if e.modified() && !e.confirm {
+			e.confirm = true
+			e.message = "The theme isn't saved, press Q again to quit"
+			return nil
+		}
+		return tea.Quit
+	}
+	return nil
+}
+
+// updateColorInput edits the color being typed, showing it as soon as
+// it's a valid color
+func (e *ThemeEditor) updateColorInput(msg tea.KeyMsg) {
+	role := theme.Roles[e.cursor]
+	switch msg.Type {
+	case tea.KeyEnter:
+		e.editing = false
+		switch {
+		case e.input == "none":
+			role.Set(&e.def, "none")
+		case theme.IsHexColor(e.input):
+			role.Set(&e.def, strings.ToLower(e.input))
+		case e.input == "" || e.input == "#":
+			role.Set(&e.def, e.before)
+		default:
+			role.Set(&e.def, e.before)
+			e.message = fmt.Sprintf("%q is not a color such as #ff79c6", e.input)
+		}
+	case tea.KeyEsc:
+		e.editing = false
+		role.Set(&e.def, e.before)
+	case tea.KeyBackspace:
+		if e.input != "" {
+			e.input = e.input[:len(e.input)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if len(e.input) < len("#rrggbb") && strings.ContainsRune("#0123456789abcdefABCDEFnoe", r) {
+				e.input += string(r)
+			}
+		}
+	default:
+		return
+	}
+
+	if e.editing && theme.IsHexColor(e.input) {
+		role.Set(&e.def, strings.ToLower(e.input))
+	}
+	e.rebuild()
+}
+
+func (e *ThemeEditor) updateName(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if e.input == "" {
+			e.message = "The theme needs a name"
+			return nil
+		}
+		e.def.Name = e.input
+		path, err := theme.SaveTheme(&e.def, "Made with 'arcade theme new'", e.confirm)
+		if errors.Is(err, theme.ErrThemeExists) {
+			e.confirm = true
+			e.message = fmt.Sprintf("%s already exists, press Enter again to replace it", filepath.Base(path))
+			return nil
+		}
+		if err != nil {
+			e.message = err.Error()
+			return nil
+		}
+		e.savedName, e.savedPath = e.def.Name, path
+		return tea.Quit
+	case tea.KeyEsc:
+		e.step = editColorsStep
+	case tea.KeyBackspace:
+		if e.input != "" {
+			e.input = e.input[:len(e.input)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+				e.input += string(r)
+			}
+		}
+	}
+	e.confirm = false
+	e.message = ""
+	return nil
+}
+
+// modified reports whether any color was changed
+func (e *ThemeEditor) modified() bool {
+	for _, role := range theme.Roles {
+		if role.Setting(&e.def) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuild makes the preview from the colors set so far
+func (e *ThemeEditor) rebuild() {
+	preview, err := theme.FromDefinition(&e.def)
+	if err != nil {
+		e.message = err.Error()
+		return
+	}
+	e.preview = preview
+}
+
+func (e *ThemeEditor) View() string {
+	var left, help string
+	preview := e.preview
+
+	switch e.step {
+	case pickBaseStep:
+		left = e.viewPickBase()
+		help = "↑ ↓ to choose, Enter to start from it, Q to quit"
+		preview, _ = theme.GetTheme(e.themes[e.cursor])
+	case editColorsStep:
+		left = e.viewColors()
+		help = "↑ ↓ to choose, Enter to change, X to reset, S to save, Q to quit"
+		if e.editing {
+			help = "Type a hex color or none, Enter to keep it, Esc to cancel"
+		}
+	case nameThemeStep:
+		left = e.viewName()
+		help = "Enter to save, Esc to go back"
+	}
+
+	if e.message != "" {
+		left += "\n\n" + styles.GetWarningStyle().Render(e.message)
+	}
+
+	right := ThemeSamples(preview) + "\n\n" + ThemeBoards(preview)
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, left, "    ", right),
+		"",
+		styles.GetHelpStyle().Render(help),
+	)
+	return lipgloss.Place(e.width, e.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (e *ThemeEditor) viewPickBase() string {
+	lines := []string{styles.GetTitleStyle().Render("New Theme"), "Start from:", ""}
+	for i, name := range e.themes {
+		if i == e.cursor {
+			lines = append(lines, styles.GetSelectedItemStyle().Render("> "+name))
+		} else {
+			lines = append(lines, styles.GetMenuItemStyle().Render("  "+name))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ThemeEditor) viewColors() string {
+	lines := []string{styles.GetTitleStyle().Render("New Theme from " + e.def.Extends)}
+	for i, role := range theme.Roles {
+		color := role.Color(e.preview)
+		value := theme.ColorString(color)
+		if i == e.cursor && e.editing {
+			value = e.input + "▏"
+		}
+		swatch := lipgloss.NewStyle().Background(color).Render("    ")
+
+		// Changed colors stand out from the inherited ones
+		changed := " "
+		if role.Setting(&e.def) != "" {
+			changed = "•"
+		}
+		line := fmt.Sprintf("%s %-24s %-8s ", changed, role.Key, value)
+
+		switch {
+		case i == e.cursor:
+			line = styles.GetSelectedItemStyle().Render(line)
+		default:
+			line = styles.GetMenuItemStyle().Render(line)
+		}
+		lines = append(lines, line+swatch)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ThemeEditor) viewName() string {
+	dir, _ := theme.UserThemeDir()
+	return strings.Join([]string{
+		styles.GetTitleStyle().Render("Save Theme"),
+		"Name: " + styles.GetSelectedItemStyle().Render(e.input+"▏"),
+		"",
+		styles.GetMenuItemStyle().Render("Saved as " + filepath.Join(dir, e.input+".yaml")),
+		styles.GetMenuItemStyle().Render("with the colors you changed, extending " + e.def.Extends),
+	}, "\n")
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/theme"
+)
+
+// ThemeSamples renders sample text in every color of a theme
+func ThemeSamples(t theme.Theme) string {
+	s := theme.NewStylesWithTheme(t)
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "UI Colors:")
+	fmt.Fprintf(&b, "  Primary:   %s\n", s.TitleStyle().UnsetMarginBottom().Render("Sample Primary Text"))
+	fmt.Fprintf(&b, "  Secondary: %s\n", s.MenuItemStyle().Render("Sample Secondary Text"))
+	fmt.Fprintf(&b, "  Accent:    %s\n", s.SelectedItemStyle().Render("Sample Accent Text"))
+	fmt.Fprintf(&b, "  Success:   %s\n", s.SuccessStyle().Render("Sample Success Text"))
+	fmt.Fprintf(&b, "  Warning:   %s\n", s.WarningStyle().Render("Sample Warning Text"))
+	fmt.Fprintf(&b, "  Error:     %s\n", s.ErrorStyle().Render("Sample Error Text"))
+
+	fmt.Fprintln(&b, "\nGame Colors:")
+	fmt.Fprintf(&b, "  Player 1:    %s\n", s.Player1Style().Render("●"))
+	fmt.Fprintf(&b, "  Player 2:    %s\n", s.Player2Style().Render("●"))
+	fmt.Fprintf(&b, "  Snake Body:  %s\n", s.SnakeStyle().Render("●"))
+	fmt.Fprintf(&b, "  Snake Head:  %s\n", s.SnakeHeadStyle().Render("◉"))
+	fmt.Fprintf(&b, "  Food:        %s\n", s.FoodStyle().Render("◆"))
+
+	fmt.Fprintln(&b, "\nChess Pieces:")
+	fmt.Fprintf(&b, "  White: %s\n", s.WhitePieceStyle().Render("♔ ♕ ♖ ♗ ♘ ♙"))
+	fmt.Fprintf(&b, "  Black: %s\n", s.BlackPieceStyle().Render("♚ ♛ ♜ ♝ ♞ ♟"))
+
+	fmt.Fprintln(&b, "\nTetris Pieces:")
+	fmt.Fprintf(&b, "  I: %s  ", s.TetrisPieceStyle("I").Render("████"))
+	fmt.Fprintf(&b, "O: %s  ", s.TetrisPieceStyle("O").Render("██"))
+	fmt.Fprintf(&b, "T: %s  ", s.TetrisPieceStyle("T").Render("███"))
+	fmt.Fprintf(&b, "S: %s\n", s.TetrisPieceStyle("S").Render("██"))
+	fmt.Fprintf(&b, "  Z: %s  ", s.TetrisPieceStyle("Z").Render("██"))
+	fmt.Fprintf(&b, "J: %s  ", s.TetrisPieceStyle("J").Render("███"))
+	fmt.Fprintf(&b, "L: %s", s.TetrisPieceStyle("L").Render("███"))
+
+	return b.String()
+}
+
+// ThemeBoards renders small snake, tetris and chess boards in a theme's
+// colors, side by side
+func ThemeBoards(t theme.Theme) string {
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		miniSnake(t), "  ", miniTetris(t), "  ", miniChess(t),
+	)
+}
+
+// miniFrame draws rows of rendered cells, width columns wide, in a board
+// border
+func miniFrame(t theme.Theme, rows []string, width int) string {
+	border := lipgloss.NewStyle().Foreground(t.BoardBorder())
+	lines := []string{border.Render("┌" + strings.Repeat("─", width) + "┐")}
+	for _, row := range rows {
+		lines = append(lines, border.Render("│")+row+border.Render("│"))
+	}
+	lines = append(lines, border.Render("└"+strings.Repeat("─", width)+"┘"))
+	return strings.Join(lines, "\n")
+}
+
+func miniSnake(t theme.Theme) string {
+	s := theme.NewStylesWithTheme(t)
+	body, head := s.SnakeStyle().Render("●"), s.SnakeHeadStyle().Render("◉")
+	food, wall := s.FoodStyle().Render("◆"), s.BorderStyle().Render("▓")
+
+	rows := []string{
+		"            ",
+		"  " + strings.Repeat(body, 4) + head + "     ",
+		"  " + body + "         ",
+		"  " + body + "     " + food + "   ",
+		"            ",
+		strings.Repeat(wall, 3) + "         ",
+	}
+	return miniFrame(t, rows, 12)
+}
+
+func miniTetris(t theme.Theme) string {
+	s := theme.NewStylesWithTheme(t)
+	// Each letter is a block of that piece, '.' is empty
+	board := []string{
+		"......",
+		"T.....",
+		"TTJ.OO",
+		"TLJJOO",
+		"LLIIII",
+		"SSZZ.I",
+	}
+	var rows []string
+	for _, line := range board {
+		var row strings.Builder
+		for _, cell := range line {
+			if cell == '.' {
+				row.WriteString("  ")
+			} else {
+				row.WriteString(s.TetrisPieceStyle(string(cell)).Render("██"))
+			}
+		}
+		rows = append(rows, row.String())
+	}
+	return miniFrame(t, rows, 12)
+}
+
+func miniChess(t theme.Theme) string {
+	s := theme.NewStylesWithTheme(t)
+	board := []string{
+		"♜♞♝♛♚♝♞♜",
+		"♟♟♟♟ ♟♟♟",
+		"    ♟   ",
+		"    ♙   ",
+		"♙♙♙♙ ♙♙♙",
+		"♖♘♗♕♔♗♘♖",
+	}
+	var rows []string
+	for y, line := range board {
+		var row strings.Builder
+		for x, piece := range []rune(line) {
+			style := s.WhitePieceStyle()
+			if strings.ContainsRune("♚♛♜♝♞♟", piece) {
+				style = s.BlackPieceStyle()
+			}
+			// Dark squares as the chess board draws them
+			if (x+y)%2 == 1 {
+				style = style.Background(theme.ChessDarkSquare)
+			}
+			if x == 4 && y == 3 {
+				style = style.Background(t.SelectedCell())
+			}
+			row.WriteString(style.Render(string(piece) + " "))
+		}
+		rows = append(rows, row.String())
+	}
+	return miniFrame(t, rows, 16)
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// toastDuration is how long a notification stays on screen
+const toastDuration = 4 * time.Second
+
+// toast is a short notification shown in the top right corner
+type toast struct {
+	id      int
+	title   string
+	text    string
+	isError bool
+}
+
+// toasts are the notifications on screen, newest last
+type toasts []toast
+
+// toastExpiredMsg removes a toast once its time is up
+type toastExpiredMsg struct {
+	id int
+}
+
+// lastToastID hands out toast IDs, unique across hosts
+var lastToastID int
+
+// push shows a notification and returns the command that removes it
+func (t *toasts) push(title, text string, isError bool) tea.Cmd {
+	lastToastID++
+	id := lastToastID
+	*t = append(*t, toast{id: id, title: title, text: text, isError: isError})
+
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// expire removes the toast of the message, reporting whether it was ours
+func (t *toasts) expire(msg toastExpiredMsg) bool {
+	for i, toast := range *t {
+		if toast.id == msg.id {
+			*t = append((*t)[:i], (*t)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// render draws the toasts stacked down the top right corner of view
+func (t toasts) render(view string) string {
+	width := lipgloss.Width(view)
+	y := 0
+	for _, toast := range t {
+		titleStyle := styles.GetSelectedItemStyle()
+		if toast.isError {
+			titleStyle = styles.GetErrorStyle()
+		}
+		box := overlayBox(lipgloss.JoinVertical(lipgloss.Left,
+			titleStyle.Render(toast.title),
+			styles.GetMenuItemStyle().Render(toast.text),
+		))
+
+		view = PlaceOverlay(max(width-lipgloss.Width(box)-1, 0), y, box, view)
+		y += lipgloss.Height(box)
+	}
+	return view
+}
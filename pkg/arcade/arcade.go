@@ -0,0 +1,96 @@
+// Package arcade embeds the arcade's games in other Bubble Tea programs.
+//
+// New returns a tea.Model running one game, with the same pause and
+// game-over screens as the arcade itself:
+//
+//	game, err := arcade.New("tetris",
+//		arcade.WithOption("level", "3"),
+//		arcade.WithTheme("dracula"),
+//		arcade.OnGameOver(func(r arcade.Result) { log.Printf("scored %d", r.Score) }),
+//	)
+//
+// Forward every message to the model, not just key presses, since the
+// game schedules its own ticks. The host decides how the player leaves
+// the game; the model does not react to ESC or Ctrl+C.
+//
+// Embedded games leave no trace: they are not added to the player's
+// scores or replays and don't unlock achievements.
+package arcade
+
+import (
+	"errors"
+
+	"github.com/jakmaz/arcade/internal/core"
+	_ "github.com/jakmaz/arcade/internal/games"
+	"github.com/jakmaz/arcade/internal/theme"
+	"github.com/jakmaz/arcade/internal/ui/styles"
+)
+
+// GameInfo describes a game that can be played with New
+type GameInfo struct {
+	ID          string
+	Name        string
+	Description string
+	Options     []OptionInfo
+}
+
+// OptionInfo documents an option accepted by a game
+type OptionInfo struct {
+	Key     string
+	Values  []string // accepted values, empty for free-form values
+	Default string
+	Help    string
+}
+
+// Games lists the available games, sorted by ID
+func Games() []GameInfo {
+	var games []GameInfo
+	for _, game := range core.AvailableGames() {
+		info := GameInfo{
+			ID:          game.ID,
+			Name:        game.Name,
+			Description: game.Description,
+		}
+		for _, option := range game.Options {
+			info.Options = append(info.Options, OptionInfo{
+				Key:     option.Key,
+				Values:  option.Values,
+				Default: option.Default,
+				Help:    option.Help,
+			})
+		}
+		games = append(games, info)
+	}
+	return games
+}
+
+// Themes lists the names of the available themes
+func Themes() []string {
+	theme.Initialize()
+	return theme.ListThemes()
+}
+
+// ThemeErrors reports the user's theme files that failed to load, which
+// Themes leaves out. It returns nil when every theme loaded.
+func ThemeErrors() error {
+	theme.Initialize()
+	return errors.Join(theme.Warnings()...)
+}
+
+// SetTheme switches every game to the named theme. Themes are shared by
+// all games in the program, including ones already running.
+func SetTheme(name string) error {
+	theme.Initialize()
+	if err := theme.SetCurrentTheme(name); err != nil {
+		return err
+	}
+	styles.RefreshStyles()
+	return nil
+}
+
+// SetColorblind turns colorblind mode on or off for every game: colors
+// that work with any color vision deficiency, and tetris pieces and
+// players told apart by shape as well as color
+func SetColorblind(on bool) {
+	styles.SetColorblind(on)
+}
@@ -0,0 +1,161 @@
+package arcade
+
+import (
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jakmaz/arcade/internal/core"
+	"github.com/jakmaz/arcade/internal/ui"
+)
+
+// Result is the outcome of a game, or its progress while it is running
+type Result struct {
+	Game     string
+	Mode     string // e.g. "levels" for snake, empty before a mode is chosen
+	Score    int
+	Over     bool
+	Outcome  string // "win", "loss" or "draw" for the first player, empty for solo games
+	Winner   string // empty for draws and solo games
+	Duration time.Duration
+}
+
+// Option configures a game created by New
+type Option func(*config)
+
+type config struct {
+	options    core.Options
+	theme      string
+	colorblind bool
+	onGameOver func(Result)
+	onScore    func(Result)
+}
+
+// WithOption sets one of the game's options, as listed by Games
+func WithOption(key, value string) Option {
+	return func(c *config) {
+		c.options[key] = value
+	}
+}
+
+// WithSeed makes the game play out the same way every time for the same
+// moves
+func WithSeed(seed uint64) Option {
+	return WithOption(core.SeedOption, strconv.FormatUint(seed, 10))
+}
+
+// WithTheme selects the theme, like SetTheme
+func WithTheme(name string) Option {
+	return func(c *config) {
+		c.theme = name
+	}
+}
+
+// WithColorblind turns on colorblind mode, like SetColorblind
+func WithColorblind() Option {
+	return func(c *config) {
+		c.colorblind = true
+	}
+}
+
+// OnGameOver calls f whenever a game finishes. Restarting from the
+// game-over screen plays another game, which calls f again when it ends.
+func OnGameOver(f func(Result)) Option {
+	return func(c *config) {
+		c.onGameOver = f
+	}
+}
+
+// OnScore calls f whenever the score changes
+func OnScore(f func(Result)) Option {
+	return func(c *config) {
+		c.onScore = f
+	}
+}
+
+// Model is an embeddable game. Callbacks run inside Update, so they
+// should return quickly.
+type Model struct {
+	gameID  string
+	session *ui.Session
+	config  config
+	last    Result
+}
+
+// New creates the game with the given ID, as listed by Games
+func New(gameID string, opts ...Option) (*Model, error) {
+	c := config{options: core.Options{}}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.theme != "" {
+		if err := SetTheme(c.theme); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.colorblind {
+		SetColorblind(true)
+	}
+
+	session, err := ui.NewEphemeralSession(gameID, c.options)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{gameID: gameID, session: session, config: c}
+	m.last = m.Result()
+	return m, nil
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.session.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmd := m.session.Update(msg)
+	m.notify()
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	return m.session.View()
+}
+
+// notify runs the callbacks for what changed since the last update
+func (m *Model) notify() {
+	result := m.Result()
+	previous := m.last
+	m.last = result
+
+	if result.Score != previous.Score && m.config.onScore != nil {
+		m.config.onScore(result)
+	}
+	if result.Over && !previous.Over && m.config.onGameOver != nil {
+		m.config.onGameOver(result)
+	}
+}
+
+// Result reports the current state of the game
+func (m *Model) Result() Result {
+	state := m.session.Game().State()
+	return Result{
+		Game:     m.gameID,
+		Mode:     state.Mode,
+		Score:    state.Score,
+		Over:     state.Over,
+		Outcome:  state.Outcome.String(),
+		Winner:   state.Winner,
+		Duration: m.session.Elapsed(),
+	}
+}
+
+// Pause freezes the game, e.g. while the host shows something else
+func (m *Model) Pause() {
+	m.session.Pause()
+}
+
+func (m *Model) Resume() {
+	m.session.Resume()
+}